@@ -0,0 +1,1750 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+	"strings"
+	"unsafe"
+
+	"github.com/dgraph-io/badger/v3/skl"
+	"github.com/dgraph-io/badger/v3/y"
+	"github.com/glycerine/uart"
+	gbtree "github.com/google/btree"
+	tbtree "github.com/tidwall/btree"
+	"github.com/zhangyunhao116/skipmap"
+)
+
+// entry is one key in both of the representations the contenders
+// use: itemT for the B-trees, and a []byte key for uART and the
+// skiplists. Scenarios hand each structure its native form, so no
+// conversion happens inside the timed loop. The (bytes) B-trees take
+// the []byte key, and the (str) radix tree and skiplist the string
+// key, through a conversion that copies nothing.
+type entry struct {
+	item itemT
+	bkey []byte
+}
+
+// stringBytes returns the bytes of s without copying them, for the
+// (str) contenders, which take the same string keys as the B-trees.
+// The bytes must not be written to.
+func stringBytes(s keyT) []byte {
+	return unsafe.Slice(unsafe.StringData(string(s)), len(s))
+}
+
+func entries(items []itemT, bkeys [][]byte) []entry {
+	ents := make([]entry, len(items))
+	for i := range items {
+		ents[i] = entry{item: items[i], bkey: bkeys[i]}
+	}
+	return ents
+}
+
+// impl is one contender in the generic scenarios. The per-library
+// blocks in main spell every call out by hand; the scenarios added
+// since then go through impl so that each new scenario covers every
+// structure, and each new structure gets every scenario.
+type impl interface {
+	name() string
+	// reset replaces the structure with a new, empty one.
+	reset()
+	set(e entry)
+	get(e entry) bool
+	len() int
+}
+
+// What else a contender can do is found by type assertion.
+
+type deleter interface {
+	del(e entry) bool
+}
+
+// ascender visits keys >= from in ascending order until fn
+// returns false.
+type ascender interface {
+	ascend(from entry, fn func() bool)
+}
+
+// descender visits keys <= from in descending order until fn
+// returns false.
+type descender interface {
+	descend(from entry, fn func() bool)
+}
+
+// boundedRanger visits keys in [lo, hi) through the library's own
+// bounded range API, or its idiom for one.
+type boundedRanger interface {
+	ascendRange(lo, hi entry, fn func() bool)
+}
+
+// boundedDescender visits keys in (lo, hi] in descending order, the
+// mirror of boundedRanger: as many keys, walked the other way.
+type boundedDescender interface {
+	descendRange(lo, hi entry, fn func() bool)
+}
+
+// iterScanner walks every key with the library's iterator, from the
+// first key forward or from the last key backward.
+type iterScanner interface {
+	iterScan(desc bool, fn func() bool)
+}
+
+// manualRanger visits keys in [lo, hi) with an open-ended ascend from
+// lo, checking each key against hi itself.
+type manualRanger interface {
+	ascendUntil(lo, hi entry, fn func() bool)
+}
+
+// prefixScanner visits every key that starts with prefix, in order,
+// until fn returns false. Only prefix.item.key and prefix.bkey are set.
+type prefixScanner interface {
+	scanPrefix(prefix entry, fn func() bool)
+}
+
+// prefixRanger is a prefixScanner that can also take the end of the
+// prefix's range, end = prefixEnd(prefix), worked out ahead of time so
+// the scan itself allocates nothing. A nil end.bkey means the range is
+// open above.
+type prefixRanger interface {
+	scanPrefixRange(prefix, end entry, fn func() bool)
+}
+
+// snapshotter takes a copy-on-write snapshot: a new contender that
+// shares nodes with the original until either one is written to.
+type snapshotter interface {
+	snapshot() impl
+}
+
+// liveReader reports whether the structure does its own locking, so
+// that readers can share it with a writer without snapshots.
+type liveReader interface {
+	locked() bool
+}
+
+// loader is the library's bulk load: an insert that is fastest when
+// keys come in ascending order.
+type loader interface {
+	load(e entry)
+}
+
+// hintSetter inserts with a path hint that the contender keeps from
+// one call to the next; reset clears it.
+type hintSetter interface {
+	setHint(e entry)
+}
+
+// leafInserter inserts a leaf the caller has already allocated.
+type leafInserter interface {
+	insertLeaf(lf *uart.Leaf)
+}
+
+// leafFiller reports how full the leaf nodes are, from 0 to 1.
+type leafFiller interface {
+	leafFill() float64
+}
+
+// arenaUser reports how much of its pre-sized arena is in use.
+type arenaUser interface {
+	arena() (used, size int64)
+}
+
+// arenaReserver is implemented by contenders whose arena is sized at
+// reset and never grows or frees. Scenarios with more keys or writes
+// than the structure holds at once call reserveArena before filling.
+type arenaReserver interface {
+	reserveArena(keys, writes int)
+}
+
+// indexer gives positional access by the index of a key in sorted
+// order. getAt and deleteAt report whether index i held want.
+type indexer interface {
+	getAt(i int, want entry) bool
+	deleteAt(i int, want entry) bool
+	rank(e entry) int
+}
+
+// minPopper finds and removes the smallest key, and maxPopper the
+// largest. Each reports whether the key was want.
+type minPopper interface {
+	peekMin(want entry) bool
+	popMin(want entry) bool
+}
+
+type maxPopper interface {
+	peekMax(want entry) bool
+	popMax(want entry) bool
+}
+
+// keyLimiter is implemented by contenders that cannot take keys
+// shorter than minKeyLen bytes. Scenarios with shorter keys skip them.
+type keyLimiter interface {
+	minKeyLen() int
+}
+
+// builder builds the whole structure from ents, in any order, faster
+// than one set at a time. fill uses it.
+type builder interface {
+	build(ents []entry)
+}
+
+// slowWriter is implemented by contenders whose writes can cost O(n):
+// all of them, or only those mixed with ordered reads (ordered), like
+// a pop. Scenarios give them fewer ops; see writeOps.
+type slowWriter interface {
+	slowWrites(ordered bool) bool
+}
+
+// linearSeeker is implemented by contenders that can only begin an
+// ordered scan at the start, so seeking to a pivot costs O(n).
+// Scenarios give them fewer ops.
+type linearSeeker interface {
+	linearSeek()
+}
+
+// timingPreparer is implemented by contenders with work to do before
+// every timed run, such as refilling a pool of nodes allocated ahead,
+// so that the work is not timed.
+type timingPreparer interface {
+	prepareTiming()
+}
+
+// timingHooks are the prepareTiming methods of the contenders newImpls
+// made last. benchOps, churn and writeRead run them through
+// beforeTiming just before they start the clock.
+var timingHooks []func()
+
+func beforeTiming() {
+	for _, h := range timingHooks {
+		h()
+	}
+}
+
+// newImpls makes every contender, and replaces timingHooks with theirs.
+func newImpls(degree, count int) []impl {
+	impls := []impl{
+		&googleImpl{degree: degree},
+		&googleGImpl{degree: degree},
+		&googleKImpl{degree: degree},
+		&googleBImpl{degree: degree},
+		&tidwallImpl{degree: degree},
+		&tidwallGImpl{degree: degree},
+		&tidwallMImpl{degree: degree},
+		&tidwallSImpl{},
+		&tidwallBImpl{degree: degree},
+		&tidwallGImpl{degree: degree, locking: true},
+		&badgerImpl{count: count},
+		&skipmapImpl{},
+		&skipmapImpl{str: true},
+		&uartImpl{},
+		&uartImpl{nolock: true},
+		&uartImpl{leaf: true, count: count},
+		&uartImpl{str: true},
+		&sliceImpl{},
+		&mapImpl{},
+		newRBImpl(),
+		newTreapImpl(),
+	}
+	timingHooks = nil
+	for _, im := range impls {
+		if p, ok := im.(timingPreparer); ok {
+			timingHooks = append(timingHooks, p.prepareTiming)
+		}
+	}
+	return impls
+}
+
+// fill resets im and loads it with ents, untimed.
+func fill(im impl, ents []entry) {
+	im.reset()
+	load(im, ents)
+}
+
+// reserveArena sizes im's arena, if it has one, for keys different
+// keys and writes more writes to them, before fill resets it.
+func reserveArena(im impl, keys, writes int) {
+	if r, ok := im.(arenaReserver); ok {
+		r.reserveArena(keys, writes)
+	}
+}
+
+// fillSize is fill that also returns how much heap im holds after.
+func fillSize(im impl, ents []entry) uint64 {
+	im.reset()
+	base := heapAlloc()
+	load(im, ents)
+	if after := heapAlloc(); after > base {
+		return after - base
+	}
+	return 0
+}
+
+// nodeFill is the leaf fill factor of a tidwall tree whose Walk gave
+// leaves leaf slices holding items in all. Walk hands over the items
+// of internal nodes one at a time, so those are told apart by length,
+// which holds for any degree above 1.
+func nodeFill(leaves, items, degree int) float64 {
+	if leaves == 0 {
+		return 0
+	}
+	return float64(items) / float64(leaves*(degree*2-1))
+}
+
+func load(im impl, ents []entry) {
+	if b, ok := im.(builder); ok {
+		b.build(ents)
+		return
+	}
+	for _, e := range ents {
+		im.set(e)
+	}
+}
+
+// writeOps is how many of count write ops to time on im: linearOps if
+// its writes cost O(n) here, where ordered tells whether the writes
+// are mixed with ordered reads.
+func writeOps(im impl, count int, ordered bool) int {
+	if sw, ok := im.(slowWriter); ok && sw.slowWrites(ordered) {
+		return min(count, linearOps)
+	}
+	return count
+}
+
+// fits reports whether im can take every key in ents.
+func fits(im impl, ents []entry) bool {
+	kl, ok := im.(keyLimiter)
+	if !ok {
+		return true
+	}
+	for _, e := range ents {
+		if len(e.bkey) < kl.minKeyLen() {
+			return false
+		}
+	}
+	return true
+}
+
+// google/btree, without generics
+
+type googleImpl struct {
+	degree int
+	tr     *gbtree.BTree
+}
+
+func (g *googleImpl) name() string     { return "google" }
+func (g *googleImpl) reset()           { g.tr = newGBTree(g.degree) }
+func (g *googleImpl) set(e entry)      { g.tr.ReplaceOrInsert(e.item) }
+func (g *googleImpl) get(e entry) bool { return g.tr.Get(e.item) != nil }
+func (g *googleImpl) del(e entry) bool { return g.tr.Delete(e.item) != nil }
+func (g *googleImpl) len() int         { return g.tr.Len() }
+
+func (g *googleImpl) ascend(from entry, fn func() bool) {
+	g.tr.AscendGreaterOrEqual(from.item, func(gbtree.Item) bool {
+		return fn()
+	})
+}
+
+func (g *googleImpl) descend(from entry, fn func() bool) {
+	g.tr.DescendLessOrEqual(from.item, func(gbtree.Item) bool {
+		return fn()
+	})
+}
+
+func (g *googleImpl) ascendRange(lo, hi entry, fn func() bool) {
+	g.tr.AscendRange(lo.item, hi.item, func(gbtree.Item) bool {
+		return fn()
+	})
+}
+
+func (g *googleImpl) descendRange(lo, hi entry, fn func() bool) {
+	g.tr.DescendRange(hi.item, lo.item, func(gbtree.Item) bool {
+		return fn()
+	})
+}
+
+func (g *googleImpl) ascendUntil(lo, hi entry, fn func() bool) {
+	g.tr.AscendGreaterOrEqual(lo.item, func(item gbtree.Item) bool {
+		return item.(itemT).key < hi.item.key && fn()
+	})
+}
+
+func (g *googleImpl) scanPrefix(prefix entry, fn func() bool) {
+	g.tr.AscendGreaterOrEqual(prefix.item, func(item gbtree.Item) bool {
+		return strings.HasPrefix(string(item.(itemT).key), string(prefix.item.key)) && fn()
+	})
+}
+
+func (g *googleImpl) snapshot() impl {
+	return &googleImpl{degree: g.degree, tr: g.tr.Clone()}
+}
+
+func (g *googleImpl) peekMin(want entry) bool { return isItem(g.tr.Min(), want) }
+func (g *googleImpl) popMin(want entry) bool  { return isItem(g.tr.DeleteMin(), want) }
+func (g *googleImpl) peekMax(want entry) bool { return isItem(g.tr.Max(), want) }
+func (g *googleImpl) popMax(want entry) bool  { return isItem(g.tr.DeleteMax(), want) }
+
+// isItem reports whether item, from one of the B-trees without
+// generics, holds want's key.
+func isItem(item any, want entry) bool {
+	return item != nil && item.(itemT).key == want.item.key
+}
+
+// google/btree, generics
+
+type googleGImpl struct {
+	degree int
+	tr     *gbtree.BTreeG[itemT]
+}
+
+func (g *googleGImpl) name() string { return "google(G)" }
+func (g *googleGImpl) reset()       { g.tr = newGBTreeG(g.degree) }
+func (g *googleGImpl) set(e entry)  { g.tr.ReplaceOrInsert(e.item) }
+func (g *googleGImpl) get(e entry) bool {
+	_, ok := g.tr.Get(e.item)
+	return ok
+}
+func (g *googleGImpl) del(e entry) bool {
+	_, ok := g.tr.Delete(e.item)
+	return ok
+}
+func (g *googleGImpl) len() int { return g.tr.Len() }
+
+func (g *googleGImpl) ascend(from entry, fn func() bool) {
+	g.tr.AscendGreaterOrEqual(from.item, func(itemT) bool {
+		return fn()
+	})
+}
+
+func (g *googleGImpl) descend(from entry, fn func() bool) {
+	g.tr.DescendLessOrEqual(from.item, func(itemT) bool {
+		return fn()
+	})
+}
+
+func (g *googleGImpl) ascendRange(lo, hi entry, fn func() bool) {
+	g.tr.AscendRange(lo.item, hi.item, func(itemT) bool {
+		return fn()
+	})
+}
+
+func (g *googleGImpl) descendRange(lo, hi entry, fn func() bool) {
+	g.tr.DescendRange(hi.item, lo.item, func(itemT) bool {
+		return fn()
+	})
+}
+
+func (g *googleGImpl) ascendUntil(lo, hi entry, fn func() bool) {
+	g.tr.AscendGreaterOrEqual(lo.item, func(item itemT) bool {
+		return item.key < hi.item.key && fn()
+	})
+}
+
+func (g *googleGImpl) scanPrefix(prefix entry, fn func() bool) {
+	g.tr.AscendGreaterOrEqual(prefix.item, func(item itemT) bool {
+		return strings.HasPrefix(string(item.key), string(prefix.item.key)) && fn()
+	})
+}
+
+func (g *googleGImpl) snapshot() impl {
+	return &googleGImpl{degree: g.degree, tr: g.tr.Clone()}
+}
+
+func (g *googleGImpl) peekMin(want entry) bool {
+	item, ok := g.tr.Min()
+	return ok && item.key == want.item.key
+}
+
+func (g *googleGImpl) popMin(want entry) bool {
+	item, ok := g.tr.DeleteMin()
+	return ok && item.key == want.item.key
+}
+
+func (g *googleGImpl) peekMax(want entry) bool {
+	item, ok := g.tr.Max()
+	return ok && item.key == want.item.key
+}
+
+func (g *googleGImpl) popMax(want entry) bool {
+	item, ok := g.tr.DeleteMax()
+	return ok && item.key == want.item.key
+}
+
+// google/btree, generics, keys only
+
+type googleKImpl struct {
+	degree int
+	tr     *gbtree.BTreeG[keyT]
+}
+
+func (g *googleKImpl) name() string { return "google(K)" }
+func (g *googleKImpl) reset()       { g.tr = newGBTreeK(g.degree) }
+func (g *googleKImpl) set(e entry)  { g.tr.ReplaceOrInsert(e.item.key) }
+func (g *googleKImpl) get(e entry) bool {
+	return g.tr.Has(e.item.key)
+}
+func (g *googleKImpl) del(e entry) bool {
+	_, ok := g.tr.Delete(e.item.key)
+	return ok
+}
+func (g *googleKImpl) len() int { return g.tr.Len() }
+
+func (g *googleKImpl) ascend(from entry, fn func() bool) {
+	g.tr.AscendGreaterOrEqual(from.item.key, func(keyT) bool {
+		return fn()
+	})
+}
+
+func (g *googleKImpl) descend(from entry, fn func() bool) {
+	g.tr.DescendLessOrEqual(from.item.key, func(keyT) bool {
+		return fn()
+	})
+}
+
+func (g *googleKImpl) ascendRange(lo, hi entry, fn func() bool) {
+	g.tr.AscendRange(lo.item.key, hi.item.key, func(keyT) bool {
+		return fn()
+	})
+}
+
+func (g *googleKImpl) descendRange(lo, hi entry, fn func() bool) {
+	g.tr.DescendRange(hi.item.key, lo.item.key, func(keyT) bool {
+		return fn()
+	})
+}
+
+func (g *googleKImpl) ascendUntil(lo, hi entry, fn func() bool) {
+	g.tr.AscendGreaterOrEqual(lo.item.key, func(key keyT) bool {
+		return key < hi.item.key && fn()
+	})
+}
+
+func (g *googleKImpl) scanPrefix(prefix entry, fn func() bool) {
+	g.tr.AscendGreaterOrEqual(prefix.item.key, func(key keyT) bool {
+		return strings.HasPrefix(string(key), string(prefix.item.key)) && fn()
+	})
+}
+
+func (g *googleKImpl) snapshot() impl {
+	return &googleKImpl{degree: g.degree, tr: g.tr.Clone()}
+}
+
+func (g *googleKImpl) peekMin(want entry) bool {
+	key, ok := g.tr.Min()
+	return ok && key == want.item.key
+}
+
+func (g *googleKImpl) popMin(want entry) bool {
+	key, ok := g.tr.DeleteMin()
+	return ok && key == want.item.key
+}
+
+func (g *googleKImpl) peekMax(want entry) bool {
+	key, ok := g.tr.Max()
+	return ok && key == want.item.key
+}
+
+func (g *googleKImpl) popMax(want entry) bool {
+	key, ok := g.tr.DeleteMax()
+	return ok && key == want.item.key
+}
+
+// google/btree, generics, []byte keys
+
+type googleBImpl struct {
+	degree int
+	tr     *gbtree.BTreeG[bitemT]
+}
+
+func (g *googleBImpl) name() string { return "google(bytes)" }
+func (g *googleBImpl) reset()       { g.tr = newGBTreeB(g.degree) }
+func (g *googleBImpl) set(e entry)  { g.tr.ReplaceOrInsert(bitem(e)) }
+func (g *googleBImpl) get(e entry) bool {
+	_, ok := g.tr.Get(bitem(e))
+	return ok
+}
+func (g *googleBImpl) del(e entry) bool {
+	_, ok := g.tr.Delete(bitem(e))
+	return ok
+}
+func (g *googleBImpl) len() int { return g.tr.Len() }
+
+func (g *googleBImpl) ascend(from entry, fn func() bool) {
+	g.tr.AscendGreaterOrEqual(bitem(from), func(bitemT) bool {
+		return fn()
+	})
+}
+
+func (g *googleBImpl) descend(from entry, fn func() bool) {
+	g.tr.DescendLessOrEqual(bitem(from), func(bitemT) bool {
+		return fn()
+	})
+}
+
+func (g *googleBImpl) ascendRange(lo, hi entry, fn func() bool) {
+	g.tr.AscendRange(bitem(lo), bitem(hi), func(bitemT) bool {
+		return fn()
+	})
+}
+
+func (g *googleBImpl) descendRange(lo, hi entry, fn func() bool) {
+	g.tr.DescendRange(bitem(hi), bitem(lo), func(bitemT) bool {
+		return fn()
+	})
+}
+
+func (g *googleBImpl) ascendUntil(lo, hi entry, fn func() bool) {
+	g.tr.AscendGreaterOrEqual(bitem(lo), func(item bitemT) bool {
+		return bytes.Compare(item.key, hi.bkey) < 0 && fn()
+	})
+}
+
+func (g *googleBImpl) scanPrefix(prefix entry, fn func() bool) {
+	g.tr.AscendGreaterOrEqual(bitem(prefix), func(item bitemT) bool {
+		return bytes.HasPrefix(item.key, prefix.bkey) && fn()
+	})
+}
+
+func (g *googleBImpl) snapshot() impl {
+	return &googleBImpl{degree: g.degree, tr: g.tr.Clone()}
+}
+
+func (g *googleBImpl) peekMin(want entry) bool {
+	item, ok := g.tr.Min()
+	return ok && bytes.Equal(item.key, want.bkey)
+}
+
+func (g *googleBImpl) popMin(want entry) bool {
+	item, ok := g.tr.DeleteMin()
+	return ok && bytes.Equal(item.key, want.bkey)
+}
+
+func (g *googleBImpl) peekMax(want entry) bool {
+	item, ok := g.tr.Max()
+	return ok && bytes.Equal(item.key, want.bkey)
+}
+
+func (g *googleBImpl) popMax(want entry) bool {
+	item, ok := g.tr.DeleteMax()
+	return ok && bytes.Equal(item.key, want.bkey)
+}
+
+// tidwall/btree, without generics
+
+type tidwallImpl struct {
+	degree int
+	tr     *tbtree.BTree
+	hint   tbtree.PathHint
+}
+
+func (t *tidwallImpl) name() string { return "tidwall" }
+func (t *tidwallImpl) reset() {
+	t.tr = newTBTree(t.degree)
+	t.hint = tbtree.PathHint{}
+}
+func (t *tidwallImpl) set(e entry)      { t.tr.Set(e.item) }
+func (t *tidwallImpl) setHint(e entry)  { t.tr.SetHint(e.item, &t.hint) }
+func (t *tidwallImpl) load(e entry)     { t.tr.Load(e.item) }
+func (t *tidwallImpl) get(e entry) bool { return t.tr.Get(e.item) != nil }
+func (t *tidwallImpl) del(e entry) bool { return t.tr.Delete(e.item) != nil }
+func (t *tidwallImpl) len() int         { return t.tr.Len() }
+
+func (t *tidwallImpl) ascend(from entry, fn func() bool) {
+	t.tr.Ascend(from.item, func(any) bool {
+		return fn()
+	})
+}
+
+func (t *tidwallImpl) descend(from entry, fn func() bool) {
+	t.tr.Descend(from.item, func(any) bool {
+		return fn()
+	})
+}
+
+// tidwall has no bounded range; Iter with Seek and a compare is the
+// way to do one.
+func (t *tidwallImpl) ascendRange(lo, hi entry, fn func() bool) {
+	iter := t.tr.Iter()
+	for ok := iter.Seek(lo.item); ok; ok = iter.Next() {
+		if iter.Item().(itemT).key >= hi.item.key || !fn() {
+			break
+		}
+	}
+	iter.Release()
+}
+
+// descendRange seeks to hi, which lands on the first key >= hi, and
+// steps back once if that key is past hi.
+func (t *tidwallImpl) descendRange(lo, hi entry, fn func() bool) {
+	iter := t.tr.Iter()
+	ok := iter.Seek(hi.item)
+	if !ok {
+		ok = iter.Last()
+	} else if iter.Item().(itemT).key > hi.item.key {
+		ok = iter.Prev()
+	}
+	for ; ok; ok = iter.Prev() {
+		if iter.Item().(itemT).key <= lo.item.key || !fn() {
+			break
+		}
+	}
+	iter.Release()
+}
+
+func (t *tidwallImpl) iterScan(desc bool, fn func() bool) {
+	iter := t.tr.Iter()
+	if desc {
+		for ok := iter.Last(); ok && fn(); ok = iter.Prev() {
+		}
+	} else {
+		for ok := iter.First(); ok && fn(); ok = iter.Next() {
+		}
+	}
+	iter.Release()
+}
+
+func (t *tidwallImpl) ascendUntil(lo, hi entry, fn func() bool) {
+	t.tr.Ascend(lo.item, func(item any) bool {
+		return item.(itemT).key < hi.item.key && fn()
+	})
+}
+
+func (t *tidwallImpl) scanPrefix(prefix entry, fn func() bool) {
+	t.tr.Ascend(prefix.item, func(item any) bool {
+		return strings.HasPrefix(string(item.(itemT).key), string(prefix.item.key)) && fn()
+	})
+}
+
+func (t *tidwallImpl) snapshot() impl {
+	return &tidwallImpl{degree: t.degree, tr: t.tr.Copy()}
+}
+
+func (t *tidwallImpl) getAt(i int, want entry) bool {
+	item := t.tr.GetAt(i)
+	return item != nil && item.(itemT).key == want.item.key
+}
+
+func (t *tidwallImpl) deleteAt(i int, want entry) bool {
+	item := t.tr.DeleteAt(i)
+	return item != nil && item.(itemT).key == want.item.key
+}
+
+// tidwall has no rank, but with GetAt a binary search finds it in
+// O(log^2 n).
+func (t *tidwallImpl) rank(e entry) int {
+	return sort.Search(t.tr.Len(), func(i int) bool {
+		return t.tr.GetAt(i).(itemT).key >= e.item.key
+	})
+}
+
+func (t *tidwallImpl) peekMin(want entry) bool { return isItem(t.tr.Min(), want) }
+func (t *tidwallImpl) popMin(want entry) bool  { return isItem(t.tr.PopMin(), want) }
+func (t *tidwallImpl) peekMax(want entry) bool { return isItem(t.tr.Max(), want) }
+func (t *tidwallImpl) popMax(want entry) bool  { return isItem(t.tr.PopMax(), want) }
+
+func (t *tidwallImpl) leafFill() float64 {
+	var leaves, items int
+	t.tr.Walk(func(slice []any) {
+		if len(slice) > 1 {
+			leaves++
+			items += len(slice)
+		}
+	})
+	return nodeFill(leaves, items, t.degree)
+}
+
+// tidwall/btree, generics; with or without its internal locking
+
+type tidwallGImpl struct {
+	degree  int
+	locking bool
+	tr      *tbtree.BTreeG[itemT]
+	hint    tbtree.PathHint
+}
+
+func (t *tidwallGImpl) name() string {
+	if t.locking {
+		return "tidwall(G) with locking"
+	}
+	return "tidwall(G)"
+}
+
+func (t *tidwallGImpl) reset() {
+	if t.locking {
+		t.tr = newTBTreeG_withLocking(t.degree)
+	} else {
+		t.tr = newTBTreeG(t.degree)
+	}
+	t.hint = tbtree.PathHint{}
+}
+
+func (t *tidwallGImpl) locked() bool { return t.locking }
+
+func (t *tidwallGImpl) set(e entry)     { t.tr.Set(e.item) }
+func (t *tidwallGImpl) setHint(e entry) { t.tr.SetHint(e.item, &t.hint) }
+func (t *tidwallGImpl) load(e entry)    { t.tr.Load(e.item) }
+func (t *tidwallGImpl) get(e entry) bool {
+	_, ok := t.tr.Get(e.item)
+	return ok
+}
+func (t *tidwallGImpl) del(e entry) bool {
+	_, ok := t.tr.Delete(e.item)
+	return ok
+}
+func (t *tidwallGImpl) len() int { return t.tr.Len() }
+
+func (t *tidwallGImpl) ascend(from entry, fn func() bool) {
+	t.tr.Ascend(from.item, func(itemT) bool {
+		return fn()
+	})
+}
+
+func (t *tidwallGImpl) descend(from entry, fn func() bool) {
+	t.tr.Descend(from.item, func(itemT) bool {
+		return fn()
+	})
+}
+
+func (t *tidwallGImpl) ascendRange(lo, hi entry, fn func() bool) {
+	iter := t.tr.Iter()
+	for ok := iter.Seek(lo.item); ok; ok = iter.Next() {
+		if iter.Item().key >= hi.item.key || !fn() {
+			break
+		}
+	}
+	iter.Release()
+}
+
+func (t *tidwallGImpl) descendRange(lo, hi entry, fn func() bool) {
+	iter := t.tr.Iter()
+	ok := iter.Seek(hi.item)
+	if !ok {
+		ok = iter.Last()
+	} else if iter.Item().key > hi.item.key {
+		ok = iter.Prev()
+	}
+	for ; ok; ok = iter.Prev() {
+		if iter.Item().key <= lo.item.key || !fn() {
+			break
+		}
+	}
+	iter.Release()
+}
+
+func (t *tidwallGImpl) iterScan(desc bool, fn func() bool) {
+	iter := t.tr.Iter()
+	if desc {
+		for ok := iter.Last(); ok && fn(); ok = iter.Prev() {
+		}
+	} else {
+		for ok := iter.First(); ok && fn(); ok = iter.Next() {
+		}
+	}
+	iter.Release()
+}
+
+func (t *tidwallGImpl) ascendUntil(lo, hi entry, fn func() bool) {
+	t.tr.Ascend(lo.item, func(item itemT) bool {
+		return item.key < hi.item.key && fn()
+	})
+}
+
+func (t *tidwallGImpl) scanPrefix(prefix entry, fn func() bool) {
+	t.tr.Ascend(prefix.item, func(item itemT) bool {
+		return strings.HasPrefix(string(item.key), string(prefix.item.key)) && fn()
+	})
+}
+
+func (t *tidwallGImpl) snapshot() impl {
+	return &tidwallGImpl{degree: t.degree, locking: t.locking, tr: t.tr.Copy()}
+}
+
+func (t *tidwallGImpl) getAt(i int, want entry) bool {
+	item, ok := t.tr.GetAt(i)
+	return ok && item.key == want.item.key
+}
+
+func (t *tidwallGImpl) deleteAt(i int, want entry) bool {
+	item, ok := t.tr.DeleteAt(i)
+	return ok && item.key == want.item.key
+}
+
+func (t *tidwallGImpl) rank(e entry) int {
+	return sort.Search(t.tr.Len(), func(i int) bool {
+		item, _ := t.tr.GetAt(i)
+		return item.key >= e.item.key
+	})
+}
+
+func (t *tidwallGImpl) peekMin(want entry) bool {
+	item, ok := t.tr.Min()
+	return ok && item.key == want.item.key
+}
+
+func (t *tidwallGImpl) popMin(want entry) bool {
+	item, ok := t.tr.PopMin()
+	return ok && item.key == want.item.key
+}
+
+func (t *tidwallGImpl) peekMax(want entry) bool {
+	item, ok := t.tr.Max()
+	return ok && item.key == want.item.key
+}
+
+func (t *tidwallGImpl) popMax(want entry) bool {
+	item, ok := t.tr.PopMax()
+	return ok && item.key == want.item.key
+}
+
+func (t *tidwallGImpl) leafFill() float64 {
+	var leaves, items int
+	t.tr.Walk(func(slice []itemT) bool {
+		if len(slice) > 1 {
+			leaves++
+			items += len(slice)
+		}
+		return true
+	})
+	return nodeFill(leaves, items, t.degree)
+}
+
+// tidwall/btree, generics, []byte keys
+
+type tidwallBImpl struct {
+	degree int
+	tr     *tbtree.BTreeG[bitemT]
+	hint   tbtree.PathHint
+}
+
+func (t *tidwallBImpl) name() string { return "tidwall(bytes)" }
+func (t *tidwallBImpl) reset() {
+	t.tr = newTBTreeB(t.degree)
+	t.hint = tbtree.PathHint{}
+}
+func (t *tidwallBImpl) set(e entry)     { t.tr.Set(bitem(e)) }
+func (t *tidwallBImpl) setHint(e entry) { t.tr.SetHint(bitem(e), &t.hint) }
+func (t *tidwallBImpl) load(e entry)    { t.tr.Load(bitem(e)) }
+func (t *tidwallBImpl) get(e entry) bool {
+	_, ok := t.tr.Get(bitem(e))
+	return ok
+}
+func (t *tidwallBImpl) del(e entry) bool {
+	_, ok := t.tr.Delete(bitem(e))
+	return ok
+}
+func (t *tidwallBImpl) len() int { return t.tr.Len() }
+
+func (t *tidwallBImpl) ascend(from entry, fn func() bool) {
+	t.tr.Ascend(bitem(from), func(bitemT) bool {
+		return fn()
+	})
+}
+
+func (t *tidwallBImpl) descend(from entry, fn func() bool) {
+	t.tr.Descend(bitem(from), func(bitemT) bool {
+		return fn()
+	})
+}
+
+func (t *tidwallBImpl) ascendRange(lo, hi entry, fn func() bool) {
+	iter := t.tr.Iter()
+	for ok := iter.Seek(bitem(lo)); ok; ok = iter.Next() {
+		if bytes.Compare(iter.Item().key, hi.bkey) >= 0 || !fn() {
+			break
+		}
+	}
+	iter.Release()
+}
+
+func (t *tidwallBImpl) descendRange(lo, hi entry, fn func() bool) {
+	iter := t.tr.Iter()
+	ok := iter.Seek(bitem(hi))
+	if !ok {
+		ok = iter.Last()
+	} else if bytes.Compare(iter.Item().key, hi.bkey) > 0 {
+		ok = iter.Prev()
+	}
+	for ; ok; ok = iter.Prev() {
+		if bytes.Compare(iter.Item().key, lo.bkey) <= 0 || !fn() {
+			break
+		}
+	}
+	iter.Release()
+}
+
+func (t *tidwallBImpl) iterScan(desc bool, fn func() bool) {
+	iter := t.tr.Iter()
+	if desc {
+		for ok := iter.Last(); ok && fn(); ok = iter.Prev() {
+		}
+	} else {
+		for ok := iter.First(); ok && fn(); ok = iter.Next() {
+		}
+	}
+	iter.Release()
+}
+
+func (t *tidwallBImpl) ascendUntil(lo, hi entry, fn func() bool) {
+	t.tr.Ascend(bitem(lo), func(item bitemT) bool {
+		return bytes.Compare(item.key, hi.bkey) < 0 && fn()
+	})
+}
+
+func (t *tidwallBImpl) scanPrefix(prefix entry, fn func() bool) {
+	t.tr.Ascend(bitem(prefix), func(item bitemT) bool {
+		return bytes.HasPrefix(item.key, prefix.bkey) && fn()
+	})
+}
+
+func (t *tidwallBImpl) snapshot() impl {
+	return &tidwallBImpl{degree: t.degree, tr: t.tr.Copy()}
+}
+
+func (t *tidwallBImpl) getAt(i int, want entry) bool {
+	item, ok := t.tr.GetAt(i)
+	return ok && bytes.Equal(item.key, want.bkey)
+}
+
+func (t *tidwallBImpl) deleteAt(i int, want entry) bool {
+	item, ok := t.tr.DeleteAt(i)
+	return ok && bytes.Equal(item.key, want.bkey)
+}
+
+func (t *tidwallBImpl) rank(e entry) int {
+	return sort.Search(t.tr.Len(), func(i int) bool {
+		item, _ := t.tr.GetAt(i)
+		return bytes.Compare(item.key, e.bkey) >= 0
+	})
+}
+
+func (t *tidwallBImpl) peekMin(want entry) bool {
+	item, ok := t.tr.Min()
+	return ok && bytes.Equal(item.key, want.bkey)
+}
+
+func (t *tidwallBImpl) popMin(want entry) bool {
+	item, ok := t.tr.PopMin()
+	return ok && bytes.Equal(item.key, want.bkey)
+}
+
+func (t *tidwallBImpl) peekMax(want entry) bool {
+	item, ok := t.tr.Max()
+	return ok && bytes.Equal(item.key, want.bkey)
+}
+
+func (t *tidwallBImpl) popMax(want entry) bool {
+	item, ok := t.tr.PopMax()
+	return ok && bytes.Equal(item.key, want.bkey)
+}
+
+func (t *tidwallBImpl) leafFill() float64 {
+	var leaves, items int
+	t.tr.Walk(func(slice []bitemT) bool {
+		if len(slice) > 1 {
+			leaves++
+			items += len(slice)
+		}
+		return true
+	})
+	return nodeFill(leaves, items, t.degree)
+}
+
+// tidwall/btree, generics using btree.Map
+
+type tidwallMImpl struct {
+	degree int
+	tr     *tbtree.Map[keyT, valT]
+}
+
+func (t *tidwallMImpl) name() string { return "tidwall(M)" }
+func (t *tidwallMImpl) reset()       { t.tr = newTBTreeM(t.degree) }
+func (t *tidwallMImpl) set(e entry)  { t.tr.Set(e.item.key, e.item.val) }
+func (t *tidwallMImpl) load(e entry) { t.tr.Load(e.item.key, e.item.val) }
+func (t *tidwallMImpl) get(e entry) bool {
+	_, ok := t.tr.Get(e.item.key)
+	return ok
+}
+func (t *tidwallMImpl) del(e entry) bool {
+	_, ok := t.tr.Delete(e.item.key)
+	return ok
+}
+func (t *tidwallMImpl) len() int { return t.tr.Len() }
+
+func (t *tidwallMImpl) ascend(from entry, fn func() bool) {
+	t.tr.Ascend(from.item.key, func(keyT, valT) bool {
+		return fn()
+	})
+}
+
+func (t *tidwallMImpl) descend(from entry, fn func() bool) {
+	t.tr.Descend(from.item.key, func(keyT, valT) bool {
+		return fn()
+	})
+}
+
+func (t *tidwallMImpl) ascendRange(lo, hi entry, fn func() bool) {
+	iter := t.tr.Iter()
+	for ok := iter.Seek(lo.item.key); ok; ok = iter.Next() {
+		if iter.Key() >= hi.item.key || !fn() {
+			break
+		}
+	}
+}
+
+func (t *tidwallMImpl) descendRange(lo, hi entry, fn func() bool) {
+	iter := t.tr.Iter()
+	ok := iter.Seek(hi.item.key)
+	if !ok {
+		ok = iter.Last()
+	} else if iter.Key() > hi.item.key {
+		ok = iter.Prev()
+	}
+	for ; ok; ok = iter.Prev() {
+		if iter.Key() <= lo.item.key || !fn() {
+			break
+		}
+	}
+}
+
+func (t *tidwallMImpl) iterScan(desc bool, fn func() bool) {
+	iter := t.tr.Iter()
+	if desc {
+		for ok := iter.Last(); ok && fn(); ok = iter.Prev() {
+		}
+	} else {
+		for ok := iter.First(); ok && fn(); ok = iter.Next() {
+		}
+	}
+}
+
+func (t *tidwallMImpl) ascendUntil(lo, hi entry, fn func() bool) {
+	t.tr.Ascend(lo.item.key, func(key keyT, _ valT) bool {
+		return key < hi.item.key && fn()
+	})
+}
+
+func (t *tidwallMImpl) scanPrefix(prefix entry, fn func() bool) {
+	t.tr.Ascend(prefix.item.key, func(key keyT, _ valT) bool {
+		return strings.HasPrefix(string(key), string(prefix.item.key)) && fn()
+	})
+}
+
+func (t *tidwallMImpl) snapshot() impl {
+	return &tidwallMImpl{degree: t.degree, tr: t.tr.Copy()}
+}
+
+func (t *tidwallMImpl) getAt(i int, want entry) bool {
+	key, _, ok := t.tr.GetAt(i)
+	return ok && key == want.item.key
+}
+
+func (t *tidwallMImpl) deleteAt(i int, want entry) bool {
+	key, _, ok := t.tr.DeleteAt(i)
+	return ok && key == want.item.key
+}
+
+func (t *tidwallMImpl) rank(e entry) int {
+	return sort.Search(t.tr.Len(), func(i int) bool {
+		key, _, _ := t.tr.GetAt(i)
+		return key >= e.item.key
+	})
+}
+
+func (t *tidwallMImpl) peekMin(want entry) bool {
+	key, _, ok := t.tr.Min()
+	return ok && key == want.item.key
+}
+
+func (t *tidwallMImpl) popMin(want entry) bool {
+	key, _, ok := t.tr.PopMin()
+	return ok && key == want.item.key
+}
+
+func (t *tidwallMImpl) peekMax(want entry) bool {
+	key, _, ok := t.tr.Max()
+	return ok && key == want.item.key
+}
+
+func (t *tidwallMImpl) popMax(want entry) bool {
+	key, _, ok := t.tr.PopMax()
+	return ok && key == want.item.key
+}
+
+// tidwall/btree Set: keys only, on a Map with empty values. It has no
+// degree option.
+
+type tidwallSImpl struct {
+	tr *tbtree.Set[keyT]
+}
+
+func (t *tidwallSImpl) name() string     { return "tidwall(S)" }
+func (t *tidwallSImpl) reset()           { t.tr = newTBTreeS() }
+func (t *tidwallSImpl) set(e entry)      { t.tr.Insert(e.item.key) }
+func (t *tidwallSImpl) load(e entry)     { t.tr.Load(e.item.key) }
+func (t *tidwallSImpl) get(e entry) bool { return t.tr.Contains(e.item.key) }
+
+// del looks first, as Set's Delete does not say whether the key was
+// there.
+func (t *tidwallSImpl) del(e entry) bool {
+	if !t.tr.Contains(e.item.key) {
+		return false
+	}
+	t.tr.Delete(e.item.key)
+	return true
+}
+func (t *tidwallSImpl) len() int { return t.tr.Len() }
+
+func (t *tidwallSImpl) ascend(from entry, fn func() bool) {
+	t.tr.Ascend(from.item.key, func(keyT) bool {
+		return fn()
+	})
+}
+
+func (t *tidwallSImpl) descend(from entry, fn func() bool) {
+	t.tr.Descend(from.item.key, func(keyT) bool {
+		return fn()
+	})
+}
+
+func (t *tidwallSImpl) ascendRange(lo, hi entry, fn func() bool) {
+	iter := t.tr.Iter()
+	for ok := iter.Seek(lo.item.key); ok; ok = iter.Next() {
+		if iter.Key() >= hi.item.key || !fn() {
+			break
+		}
+	}
+}
+
+func (t *tidwallSImpl) descendRange(lo, hi entry, fn func() bool) {
+	iter := t.tr.Iter()
+	ok := iter.Seek(hi.item.key)
+	if !ok {
+		ok = iter.Last()
+	} else if iter.Key() > hi.item.key {
+		ok = iter.Prev()
+	}
+	for ; ok; ok = iter.Prev() {
+		if iter.Key() <= lo.item.key || !fn() {
+			break
+		}
+	}
+}
+
+func (t *tidwallSImpl) iterScan(desc bool, fn func() bool) {
+	iter := t.tr.Iter()
+	if desc {
+		for ok := iter.Last(); ok && fn(); ok = iter.Prev() {
+		}
+	} else {
+		for ok := iter.First(); ok && fn(); ok = iter.Next() {
+		}
+	}
+}
+
+func (t *tidwallSImpl) ascendUntil(lo, hi entry, fn func() bool) {
+	t.tr.Ascend(lo.item.key, func(key keyT) bool {
+		return key < hi.item.key && fn()
+	})
+}
+
+func (t *tidwallSImpl) scanPrefix(prefix entry, fn func() bool) {
+	t.tr.Ascend(prefix.item.key, func(key keyT) bool {
+		return strings.HasPrefix(string(key), string(prefix.item.key)) && fn()
+	})
+}
+
+func (t *tidwallSImpl) snapshot() impl {
+	return &tidwallSImpl{tr: t.tr.Copy()}
+}
+
+func (t *tidwallSImpl) getAt(i int, want entry) bool {
+	key, ok := t.tr.GetAt(i)
+	return ok && key == want.item.key
+}
+
+func (t *tidwallSImpl) deleteAt(i int, want entry) bool {
+	key, ok := t.tr.DeleteAt(i)
+	return ok && key == want.item.key
+}
+
+func (t *tidwallSImpl) rank(e entry) int {
+	return sort.Search(t.tr.Len(), func(i int) bool {
+		key, _ := t.tr.GetAt(i)
+		return key >= e.item.key
+	})
+}
+
+func (t *tidwallSImpl) peekMin(want entry) bool {
+	key, ok := t.tr.Min()
+	return ok && key == want.item.key
+}
+
+func (t *tidwallSImpl) popMin(want entry) bool {
+	key, ok := t.tr.PopMin()
+	return ok && key == want.item.key
+}
+
+func (t *tidwallSImpl) peekMax(want entry) bool {
+	key, ok := t.tr.Max()
+	return ok && key == want.item.key
+}
+
+func (t *tidwallSImpl) popMax(want entry) bool {
+	key, ok := t.tr.PopMax()
+	return ok && key == want.item.key
+}
+
+// badger/skiplist. It has no count, and deletes the way badger does,
+// with a tombstone; the walks skip tombstones. The arena is sized up
+// front, by badgerArena, for count keys and any more writes reserved
+// with reserveArena.
+
+type badgerImpl struct {
+	count  int
+	keys   int
+	writes int
+	sl     *skl.Skiplist
+}
+
+func (s *badgerImpl) name() string { return "badger/skiplist" }
+func (s *badgerImpl) reset() {
+	s.sl = skl.NewSkiplist(badgerArena(max(s.count, s.keys), s.writes))
+}
+func (s *badgerImpl) set(e entry) {
+	s.sl.Put(e.bkey, y.ValueStruct{Value: e.bkey})
+}
+
+func (s *badgerImpl) get(e entry) bool { return badgerFound(s.sl.Get(e.bkey), e.bkey) }
+
+// del puts a tombstone, a value with the delete bit set in its meta,
+// over a key that is there. The arena never gets the space back.
+func (s *badgerImpl) del(e entry) bool {
+	if !badgerFound(s.sl.Get(e.bkey), e.bkey) {
+		return false
+	}
+	s.sl.Put(e.bkey, y.ValueStruct{Meta: badgerBitDelete})
+	return true
+}
+
+// reserveArena sizes the arena, from the next reset on, for keys
+// different keys and writes more writes to them, deletes included.
+func (s *badgerImpl) reserveArena(keys, writes int) {
+	s.keys, s.writes = keys, writes
+}
+
+// badgerArena is the arena size for keys keys and writes more writes
+// to them. The arena cannot grow, and Put panics once it is full, so
+// it gets twice what they need: the head node and a node of the
+// maximum height for each key, with its key and value (the key again),
+// and a value for each further write, a tombstone taking less.
+func badgerArena(keys, writes int) int64 {
+	node := skl.MaxNodeSize + 2*keyLen + badgerValueOverhead
+	value := keyLen + badgerValueOverhead
+	return int64(2 * ((keys+1)*node + writes*value))
+}
+
+// badgerValueOverhead is what the arena takes for a value besides its
+// bytes: its meta, user meta and expiry.
+const badgerValueOverhead = 2 + binary.MaxVarintLen64
+
+// badgerBitDelete is the meta bit badger sets on the value of a
+// deleted key, its tombstone. It is bitDelete in package badger,
+// which skl does not export.
+const badgerBitDelete byte = 1 << 0
+
+// badgerFound reports whether vs, what the skiplist returned for key,
+// is a hit. A tombstone is a miss. The value is checked too, as it is
+// the whole key: the skiplist matches a key without its version, the
+// last 8 bytes, and so also finds a key that differs only there.
+func badgerFound(vs y.ValueStruct, key []byte) bool {
+	return vs.Meta&badgerBitDelete == 0 && bytes.Equal(vs.Value, key)
+}
+
+// badgerDead reports whether the iterator is on a tombstone, which the
+// walks step over.
+func badgerDead(it *skl.Iterator) bool {
+	return it.Value().Meta&badgerBitDelete != 0
+}
+
+// badger takes the last 8 bytes of a key as its version, so a key
+// needs at least one byte more to sort by anything else.
+func (s *badgerImpl) minKeyLen() int { return 9 }
+
+func (s *badgerImpl) arena() (used, size int64) {
+	return s.sl.MemSize(), badgerArena(max(s.count, s.keys), s.writes)
+}
+
+// len walks the list; it is only used for checks, never timed.
+func (s *badgerImpl) len() (n int) {
+	it := s.sl.NewIterator()
+	defer it.Close()
+	for it.SeekToFirst(); it.Valid(); it.Next() {
+		if !badgerDead(it) {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *badgerImpl) ascend(from entry, fn func() bool) {
+	it := s.sl.NewIterator()
+	defer it.Close()
+	for it.Seek(from.bkey); it.Valid(); it.Next() {
+		if !badgerDead(it) && !fn() {
+			return
+		}
+	}
+}
+
+func (s *badgerImpl) descend(from entry, fn func() bool) {
+	it := s.sl.NewIterator()
+	defer it.Close()
+	for it.SeekForPrev(from.bkey); it.Valid(); it.Prev() {
+		if !badgerDead(it) && !fn() {
+			return
+		}
+	}
+}
+
+func (s *badgerImpl) ascendRange(lo, hi entry, fn func() bool) {
+	it := s.sl.NewIterator()
+	defer it.Close()
+	for it.Seek(lo.bkey); it.Valid(); it.Next() {
+		if bytes.Compare(it.Key(), hi.bkey) >= 0 || !badgerDead(it) && !fn() {
+			return
+		}
+	}
+}
+
+func (s *badgerImpl) descendRange(lo, hi entry, fn func() bool) {
+	it := s.sl.NewIterator()
+	defer it.Close()
+	for it.SeekForPrev(hi.bkey); it.Valid(); it.Prev() {
+		if bytes.Compare(it.Key(), lo.bkey) <= 0 || !badgerDead(it) && !fn() {
+			return
+		}
+	}
+}
+
+func (s *badgerImpl) iterScan(desc bool, fn func() bool) {
+	it := s.sl.NewIterator()
+	defer it.Close()
+	if desc {
+		for it.SeekToLast(); it.Valid() && (badgerDead(it) || fn()); it.Prev() {
+		}
+	} else {
+		for it.SeekToFirst(); it.Valid() && (badgerDead(it) || fn()); it.Next() {
+		}
+	}
+}
+
+func (s *badgerImpl) ascendUntil(lo, hi entry, fn func() bool) {
+	it := s.sl.NewIterator()
+	defer it.Close()
+	for it.Seek(lo.bkey); it.Valid(); it.Next() {
+		if bytes.Compare(it.Key(), hi.bkey) >= 0 || !badgerDead(it) && !fn() {
+			return
+		}
+	}
+}
+
+// badger splits every key into the key proper and an 8 byte version
+// at the end, and compares those separately. A short seek key would be
+// cut in the wrong place, so the prefix is padded with zeros to the
+// full keyLen bytes of our keys first.
+func (s *badgerImpl) scanPrefix(prefix entry, fn func() bool) {
+	seek := prefix.bkey
+	if len(seek) < keyLen {
+		seek = make([]byte, keyLen)
+		copy(seek, prefix.bkey)
+	}
+	it := s.sl.NewIterator()
+	defer it.Close()
+	for it.Seek(seek); it.Valid(); it.Next() {
+		if !bytes.HasPrefix(it.Key(), prefix.bkey) || !badgerDead(it) && !fn() {
+			return
+		}
+	}
+}
+
+// zhangyunhao116/skipmap. Range always starts at the smallest key
+// and there is no reverse iteration. With str, it is fed the string
+// keys through stringBytes instead of the []byte keys.
+
+type skipmapImpl struct {
+	str bool
+	m   *skipmap.FuncMap[[]byte, int]
+}
+
+func (s *skipmapImpl) name() string {
+	if s.str {
+		return "zhangyunhao116/skipmap(str)"
+	}
+	return "zhangyunhao116/skipmap"
+}
+
+func (s *skipmapImpl) key(e entry) []byte {
+	if s.str {
+		return stringBytes(e.item.key)
+	}
+	return e.bkey
+}
+func (s *skipmapImpl) reset() {
+	s.m = skipmap.NewFunc[[]byte, int](func(a, b []byte) bool {
+		return bytes.Compare(a, b) < 0
+	})
+}
+func (s *skipmapImpl) set(e entry) { s.m.Store(s.key(e), int(e.item.val)) }
+func (s *skipmapImpl) get(e entry) bool {
+	_, ok := s.m.Load(s.key(e))
+	return ok
+}
+func (s *skipmapImpl) del(e entry) bool { return s.m.Delete(s.key(e)) }
+func (s *skipmapImpl) len() int         { return s.m.Len() }
+func (s *skipmapImpl) linearSeek()      {}
+
+func (s *skipmapImpl) ascend(from entry, fn func() bool) {
+	s.m.Range(func(k []byte, _ int) bool {
+		if bytes.Compare(k, s.key(from)) < 0 {
+			return true
+		}
+		return fn()
+	})
+}
+
+// skipmap cannot go backwards, so descend collects the keys up to
+// from and hands them out last first, at O(n) time and memory.
+func (s *skipmapImpl) descend(from entry, fn func() bool) {
+	var keys [][]byte
+	s.m.Range(func(k []byte, _ int) bool {
+		if bytes.Compare(k, s.key(from)) > 0 {
+			return false
+		}
+		keys = append(keys, k)
+		return true
+	})
+	for i := len(keys) - 1; i >= 0 && fn(); i-- {
+	}
+}
+
+func (s *skipmapImpl) ascendUntil(lo, hi entry, fn func() bool) {
+	s.m.Range(func(k []byte, _ int) bool {
+		if bytes.Compare(k, s.key(lo)) < 0 {
+			return true
+		}
+		return bytes.Compare(k, s.key(hi)) < 0 && fn()
+	})
+}
+
+func (s *skipmapImpl) scanPrefix(prefix entry, fn func() bool) {
+	s.m.Range(func(k []byte, _ int) bool {
+		if bytes.Compare(k, s.key(prefix)) < 0 {
+			return true
+		}
+		return bytes.HasPrefix(k, s.key(prefix)) && fn()
+	})
+}
+
+// skipmap has no last element, so only the min side.
+func (s *skipmapImpl) peekMin(want entry) bool {
+	var found bool
+	s.m.Range(func(k []byte, _ int) bool {
+		found = bytes.Equal(k, s.key(want))
+		return false
+	})
+	return found
+}
+
+func (s *skipmapImpl) popMin(want entry) bool {
+	var first []byte
+	s.m.Range(func(k []byte, _ int) bool {
+		first = k
+		return false
+	})
+	return first != nil && bytes.Equal(first, s.key(want)) && s.m.Delete(first)
+}
+
+// uART; with or without its internal locking, and with Insert, which
+// copies the key into a new leaf, or (leaf) with InsertLeaf on a leaf
+// taken from a slab of count leaves, pointing at the caller's key.
+// reset allocates the slab and prepareTiming tops it up before every
+// timed run, so like leafset in main it is not counted in the bytes/op
+// of the sets. With str, it is fed the string keys through stringBytes
+// instead of the []byte keys.
+
+type uartImpl struct {
+	nolock bool
+	leaf   bool
+	str    bool
+	count  int
+	leaves []uart.Leaf
+	tr     *uart.Tree
+}
+
+func (u *uartImpl) name() string {
+	switch {
+	case u.nolock:
+		return "uART(nolock)"
+	case u.leaf:
+		return "uART(leaf)"
+	case u.str:
+		return "uART(str)"
+	}
+	return "uART"
+}
+
+func (u *uartImpl) key(e entry) []byte {
+	if u.str {
+		return stringBytes(e.item.key)
+	}
+	return e.bkey
+}
+func (u *uartImpl) reset() {
+	u.tr = newUART(u.nolock)
+	if u.leaf {
+		u.leaves = make([]uart.Leaf, u.count)
+	}
+}
+func (u *uartImpl) locked() bool { return !u.nolock }
+
+// prepareTiming replaces the slab with a full one if any of it was
+// used. The leaves already in the tree stay where they are.
+func (u *uartImpl) prepareTiming() {
+	if u.leaf && len(u.leaves) < u.count {
+		u.leaves = make([]uart.Leaf, u.count)
+	}
+}
+func (u *uartImpl) set(e entry) {
+	if !u.leaf {
+		u.tr.Insert(u.key(e), e.item.val)
+		return
+	}
+	// Only a timed run of more than count sets runs out; it gets
+	// another slab, timed.
+	if len(u.leaves) == 0 {
+		u.leaves = make([]uart.Leaf, max(u.count, 1))
+	}
+	lf := &u.leaves[0]
+	u.leaves = u.leaves[1:]
+	lf.Key, lf.Value = u.key(e), e.item.val
+	u.tr.InsertLeaf(lf)
+}
+func (u *uartImpl) get(e entry) bool {
+	_, _, found := u.tr.FindExact(u.key(e))
+	return found
+}
+func (u *uartImpl) del(e entry) bool {
+	deleted, _ := u.tr.Remove(u.key(e))
+	return deleted
+}
+func (u *uartImpl) len() int                 { return u.tr.Size() }
+func (u *uartImpl) insertLeaf(lf *uart.Leaf) { u.tr.InsertLeaf(lf) }
+
+func (u *uartImpl) ascend(from entry, fn func() bool) {
+	for range uart.Ascend(u.tr, u.key(from), nil) {
+		if !fn() {
+			return
+		}
+	}
+}
+
+func (u *uartImpl) descend(from entry, fn func() bool) {
+	for range uart.Descend(u.tr, u.key(from), nil) {
+		if !fn() {
+			return
+		}
+	}
+}
+
+func (u *uartImpl) ascendRange(lo, hi entry, fn func() bool) {
+	for range uart.Ascend(u.tr, u.key(lo), u.key(hi)) {
+		if !fn() {
+			return
+		}
+	}
+}
+
+func (u *uartImpl) descendRange(lo, hi entry, fn func() bool) {
+	for range uart.Descend(u.tr, u.key(hi), u.key(lo)) {
+		if !fn() {
+			return
+		}
+	}
+}
+
+func (u *uartImpl) ascendUntil(lo, hi entry, fn func() bool) {
+	for key := range uart.Ascend(u.tr, u.key(lo), nil) {
+		if bytes.Compare(key, u.key(hi)) >= 0 || !fn() {
+			return
+		}
+	}
+}
+
+// scanPrefix lets the radix tree bound the walk: every key with the
+// prefix is in [prefix, prefixEnd(prefix)).
+func (u *uartImpl) scanPrefix(prefix entry, fn func() bool) {
+	end := prefixEnd(u.key(prefix))
+	u.scanPrefixRange(prefix, entry{item: itemT{key: keyT(end)}, bkey: end}, fn)
+}
+
+// scanPrefixRange reads end in the same key form as prefix, but a nil
+// end.bkey still means that the range is open above.
+func (u *uartImpl) scanPrefixRange(prefix, end entry, fn func() bool) {
+	var hi []byte
+	if end.bkey != nil {
+		hi = u.key(end)
+	}
+	for range uart.Ascend(u.tr, u.key(prefix), hi) {
+		if !fn() {
+			return
+		}
+	}
+}
+
+// uART keeps subtree counts: At finds a leaf by index and FindExact
+// returns the index of a key. There is no remove by index, so
+// deleteAt is At and then Remove.
+func (u *uartImpl) getAt(i int, want entry) bool {
+	lf, ok := u.tr.At(i)
+	return ok && bytes.Equal(lf.Key, u.key(want))
+}
+
+func (u *uartImpl) deleteAt(i int, want entry) bool {
+	lf, ok := u.tr.At(i)
+	if !ok || !bytes.Equal(lf.Key, u.key(want)) {
+		return false
+	}
+	deleted, _ := u.tr.Remove(lf.Key)
+	return deleted
+}
+
+func (u *uartImpl) rank(e entry) int {
+	_, idx, _ := u.tr.FindExact(u.key(e))
+	return idx
+}
+
+func (u *uartImpl) peekMin(want entry) bool {
+	lf, ok := u.tr.At(0)
+	return ok && bytes.Equal(lf.Key, u.key(want))
+}
+
+func (u *uartImpl) popMin(want entry) bool { return u.deleteAt(0, want) }
+
+func (u *uartImpl) peekMax(want entry) bool {
+	lf, ok := u.tr.At(u.tr.Size() - 1)
+	return ok && bytes.Equal(lf.Key, u.key(want))
+}
+
+func (u *uartImpl) popMax(want entry) bool { return u.deleteAt(u.tr.Size()-1, want) }
+
+// prefixEnd returns the smallest key greater than every key starting
+// with prefix, or nil if there is none (the prefix is all 0xff).
+func prefixEnd(prefix []byte) []byte {
+	end := append([]byte(nil), prefix...)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// The two baselines below are not libraries; they are here to show
+// the N below which a B-tree is not worth it.
+
+// sorted slice: binary search, and insert and delete by copying the
+// tail over. Writes cost O(n).
+
+type sliceImpl struct {
+	items []itemT
+}
+
+func (s *sliceImpl) find(key keyT) (int, bool) {
+	return slices.BinarySearchFunc(s.items, key, func(item itemT, key keyT) int {
+		return cmp.Compare(item.key, key)
+	})
+}
+
+func (s *sliceImpl) name() string { return "sorted slice" }
+func (s *sliceImpl) reset()       { s.items = nil }
+func (s *sliceImpl) set(e entry) {
+	i, found := s.find(e.item.key)
+	if found {
+		s.items[i] = e.item
+	} else {
+		s.items = slices.Insert(s.items, i, e.item)
+	}
+}
+func (s *sliceImpl) get(e entry) bool {
+	_, found := s.find(e.item.key)
+	return found
+}
+func (s *sliceImpl) del(e entry) bool {
+	i, found := s.find(e.item.key)
+	if found {
+		s.items = slices.Delete(s.items, i, i+1)
+	}
+	return found
+}
+func (s *sliceImpl) len() int             { return len(s.items) }
+func (s *sliceImpl) slowWrites(bool) bool { return true }
+
+// build sorts once, where set would copy for every key.
+func (s *sliceImpl) build(ents []entry) {
+	s.items = make([]itemT, len(ents))
+	for i, e := range ents {
+		s.items[i] = e.item
+	}
+	slices.SortFunc(s.items, func(a, b itemT) int {
+		return cmp.Compare(a.key, b.key)
+	})
+}
+
+// load appends when e goes last, which is what Load does for tidwall.
+func (s *sliceImpl) load(e entry) {
+	if n := len(s.items); n == 0 || s.items[n-1].key < e.item.key {
+		s.items = append(s.items, e.item)
+		return
+	}
+	s.set(e)
+}
+
+func (s *sliceImpl) ascend(from entry, fn func() bool) {
+	for i, _ := s.find(from.item.key); i < len(s.items) && fn(); i++ {
+	}
+}
+
+func (s *sliceImpl) descend(from entry, fn func() bool) {
+	i, found := s.find(from.item.key)
+	if !found {
+		i--
+	}
+	for ; i >= 0 && fn(); i-- {
+	}
+}
+
+func (s *sliceImpl) ascendRange(lo, hi entry, fn func() bool) {
+	for i, _ := s.find(lo.item.key); i < len(s.items) && s.items[i].key < hi.item.key && fn(); i++ {
+	}
+}
+
+func (s *sliceImpl) descendRange(lo, hi entry, fn func() bool) {
+	i, found := s.find(hi.item.key)
+	if !found {
+		i--
+	}
+	for ; i >= 0 && s.items[i].key > lo.item.key && fn(); i-- {
+	}
+}
+
+func (s *sliceImpl) ascendUntil(lo, hi entry, fn func() bool) {
+	s.ascendRange(lo, hi, fn)
+}
+
+func (s *sliceImpl) scanPrefix(prefix entry, fn func() bool) {
+	i, _ := s.find(prefix.item.key)
+	for ; i < len(s.items) && strings.HasPrefix(string(s.items[i].key), string(prefix.item.key)) && fn(); i++ {
+	}
+}
+
+func (s *sliceImpl) getAt(i int, want entry) bool {
+	return i >= 0 && i < len(s.items) && s.items[i].key == want.item.key
+}
+
+func (s *sliceImpl) deleteAt(i int, want entry) bool {
+	if !s.getAt(i, want) {
+		return false
+	}
+	s.items = slices.Delete(s.items, i, i+1)
+	return true
+}
+
+func (s *sliceImpl) rank(e entry) int {
+	i, _ := s.find(e.item.key)
+	return i
+}
+
+func (s *sliceImpl) peekMin(want entry) bool { return s.getAt(0, want) }
+func (s *sliceImpl) popMin(want entry) bool  { return s.deleteAt(0, want) }
+func (s *sliceImpl) peekMax(want entry) bool { return s.getAt(len(s.items)-1, want) }
+func (s *sliceImpl) popMax(want entry) bool  { return s.deleteAt(len(s.items)-1, want) }
+
+// Go map, with its keys sorted on demand for the ordered operations.
+// keys is rebuilt and sorted on the first ordered operation after a
+// key was added or deleted, so mixing those costs O(n log n) a time.
+
+type mapImpl struct {
+	m     map[keyT]valT
+	keys  []keyT
+	stale bool
+}
+
+func (m *mapImpl) name() string { return "Go map" }
+func (m *mapImpl) reset() {
+	m.m = make(map[keyT]valT)
+	m.keys, m.stale = nil, false
+}
+func (m *mapImpl) set(e entry) {
+	if _, ok := m.m[e.item.key]; !ok {
+		m.stale = true
+	}
+	m.m[e.item.key] = e.item.val
+}
+func (m *mapImpl) get(e entry) bool {
+	_, ok := m.m[e.item.key]
+	return ok
+}
+func (m *mapImpl) del(e entry) bool {
+	if _, ok := m.m[e.item.key]; !ok {
+		return false
+	}
+	delete(m.m, e.item.key)
+	m.stale = true
+	return true
+}
+func (m *mapImpl) len() int                     { return len(m.m) }
+func (m *mapImpl) slowWrites(ordered bool) bool { return ordered }
+
+func (m *mapImpl) build(ents []entry) {
+	m.m = make(map[keyT]valT, len(ents))
+	for _, e := range ents {
+		m.m[e.item.key] = e.item.val
+	}
+	m.stale = true
+}
+
+// sorted brings keys up to date and returns them.
+func (m *mapImpl) sorted() []keyT {
+	if m.stale {
+		m.keys = m.keys[:0]
+		for k := range m.m {
+			m.keys = append(m.keys, k)
+		}
+		slices.Sort(m.keys)
+		m.stale = false
+	}
+	return m.keys
+}
+
+func (m *mapImpl) find(key keyT) (int, bool) {
+	return slices.BinarySearch(m.sorted(), key)
+}
+
+func (m *mapImpl) ascend(from entry, fn func() bool) {
+	for i, _ := m.find(from.item.key); i < len(m.keys) && fn(); i++ {
+	}
+}
+
+func (m *mapImpl) descend(from entry, fn func() bool) {
+	i, found := m.find(from.item.key)
+	if !found {
+		i--
+	}
+	for ; i >= 0 && fn(); i-- {
+	}
+}
+
+func (m *mapImpl) ascendRange(lo, hi entry, fn func() bool) {
+	for i, _ := m.find(lo.item.key); i < len(m.keys) && m.keys[i] < hi.item.key && fn(); i++ {
+	}
+}
+
+func (m *mapImpl) descendRange(lo, hi entry, fn func() bool) {
+	i, found := m.find(hi.item.key)
+	if !found {
+		i--
+	}
+	for ; i >= 0 && m.keys[i] > lo.item.key && fn(); i-- {
+	}
+}
+
+func (m *mapImpl) ascendUntil(lo, hi entry, fn func() bool) {
+	m.ascendRange(lo, hi, fn)
+}
+
+func (m *mapImpl) scanPrefix(prefix entry, fn func() bool) {
+	i, _ := m.find(prefix.item.key)
+	for ; i < len(m.keys) && strings.HasPrefix(string(m.keys[i]), string(prefix.item.key)) && fn(); i++ {
+	}
+}
+
+func (m *mapImpl) getAt(i int, want entry) bool {
+	keys := m.sorted()
+	return i >= 0 && i < len(keys) && keys[i] == want.item.key
+}
+
+// deleteAt keeps keys sorted by deleting from it too, at O(n).
+func (m *mapImpl) deleteAt(i int, want entry) bool {
+	if !m.getAt(i, want) {
+		return false
+	}
+	delete(m.m, m.keys[i])
+	m.keys = slices.Delete(m.keys, i, i+1)
+	return true
+}
+
+func (m *mapImpl) rank(e entry) int {
+	i, _ := m.find(e.item.key)
+	return i
+}
+
+func (m *mapImpl) peekMin(want entry) bool { return m.getAt(0, want) }
+func (m *mapImpl) popMin(want entry) bool  { return m.deleteAt(0, want) }
+func (m *mapImpl) peekMax(want entry) bool { return m.getAt(len(m.m)-1, want) }
+func (m *mapImpl) popMax(want entry) bool  { return m.deleteAt(len(m.m)-1, want) }
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestSlice(t *testing.T) { checkImpl(t, &sliceImpl{}) }
+func TestMap(t *testing.T)   { checkImpl(t, &mapImpl{}) }
+
+func testEntry(i int) entry {
+	item := int64ToItemT(int64(i))
+	return entry{item: item, bkey: []byte(item.key)}
+}
+
+// checkImpl runs random sets, gets and deletes on im and after every
+// few of them compares it with a sorted slice of the keys it should
+// hold: its len, how many keys ascend and descend visit from each key,
+// and, where im has them, getAt, rank and the min/max pops.
+func checkImpl(t *testing.T, im impl) {
+	t.Helper()
+	const space = 200
+	r := rand.New(rand.NewSource(1))
+	im.reset()
+	var model []int
+	for op := 1; op <= 5000; op++ {
+		k := r.Intn(space)
+		i, found := slices.BinarySearch(model, k)
+		switch r.Intn(3) {
+		case 0, 1:
+			im.set(testEntry(k))
+			if !found {
+				model = slices.Insert(model, i, k)
+			}
+		case 2:
+			if d, ok := im.(deleter); ok {
+				if got := d.del(testEntry(k)); got != found {
+					t.Fatalf("op %d: del(%d) = %v, want %v", op, k, got, found)
+				}
+				if found {
+					model = slices.Delete(model, i, i+1)
+				}
+			}
+		}
+		if got := im.get(testEntry(k)); got != slices.Contains(model, k) {
+			t.Fatalf("op %d: get(%d) = %v, want %v", op, k, got, !got)
+		}
+		if op%250 == 0 {
+			checkOrder(t, im, model, space)
+		}
+	}
+	checkPops(t, im, model)
+}
+
+// checkOrder checks the ordered reads of im against model.
+func checkOrder(t *testing.T, im impl, model []int, space int) {
+	t.Helper()
+	if im.len() != len(model) {
+		t.Fatalf("len = %d, want %d", im.len(), len(model))
+	}
+	count := func(walk func(from entry, fn func() bool), from int) int {
+		var n int
+		walk(testEntry(from), func() bool {
+			n++
+			return true
+		})
+		return n
+	}
+	for k := 0; k <= space; k++ {
+		lo, found := slices.BinarySearch(model, k)
+		hi := lo
+		if found {
+			hi++
+		}
+		if a, ok := im.(ascender); ok {
+			if got := count(a.ascend, k); got != len(model)-lo {
+				t.Fatalf("ascend from %d visited %d, want %d", k, got, len(model)-lo)
+			}
+		}
+		if d, ok := im.(descender); ok {
+			if got := count(d.descend, k); got != hi {
+				t.Fatalf("descend from %d visited %d, want %d", k, got, hi)
+			}
+		}
+	}
+	ix, ok := im.(indexer)
+	if !ok {
+		return
+	}
+	for i, k := range model {
+		if !ix.getAt(i, testEntry(k)) {
+			t.Fatalf("getAt(%d) is not %d", i, k)
+		}
+		if i > 0 && ix.getAt(i, testEntry(model[i-1])) {
+			t.Fatalf("getAt(%d) is %d, want %d", i, model[i-1], k)
+		}
+		if got := ix.rank(testEntry(k)); got != i {
+			t.Fatalf("rank(%d) = %d, want %d", k, got, i)
+		}
+	}
+}
+
+// checkPops empties im from both ends, checking each key against
+// model.
+func checkPops(t *testing.T, im impl, model []int) {
+	t.Helper()
+	mn, ok1 := im.(minPopper)
+	mx, ok2 := im.(maxPopper)
+	if !ok1 || !ok2 {
+		return
+	}
+	for len(model) > 0 {
+		if k := model[0]; !mn.peekMin(testEntry(k)) || !mn.popMin(testEntry(k)) {
+			t.Fatalf("min is not %d", k)
+		}
+		model = model[1:]
+		if len(model) == 0 {
+			break
+		}
+		if k := model[len(model)-1]; !mx.peekMax(testEntry(k)) || !mx.popMax(testEntry(k)) {
+			t.Fatalf("max is not %d", k)
+		}
+		model = model[:len(model)-1]
+	}
+	if im.len() != 0 {
+		t.Fatalf("len = %d after popping every key", im.len())
+	}
+}
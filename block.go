@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+)
+
+// -warmup is the number of untimed ops each run does before it is
+// timed. Zero means no warmup, as before.
+var warmupOps int
+
+// -shuffle randomizes the order of the runs within each block, so the
+// first contender (usually google) does not always pay for page faults
+// and cold caches.
+var shuffleRuns bool
+
+// -cold times each run twice: once right after evicting the CPU caches,
+// and once after a warmup pass. Both numbers are reported.
+var coldMode bool
+
+// -coldbuf is the size in MB of the buffer touched to evict the caches.
+// It should be comfortably larger than the last level cache.
+var coldBufMB = 256
+
+// run is one contender in a block. setup is untimed and must leave
+// the structure ready for op, so that a run can be repeated, e.g. once
+// for the warmup and again for the timing.
+type run struct {
+	label  string
+	action string
+	count  int // 0 means the block's count
+	setup  func()
+	op     func(i, thread int)
+}
+
+// block is a group of runs that are compared with each other, such as
+// "** random set **". Runs are executed by block.exec, in the order they
+// were added unless -shuffle is given.
+type block struct {
+	count   int
+	runs    []run
+	results map[runKey]result
+}
+
+type runKey struct{ label, action string }
+
+func newBlock(count int) *block {
+	return &block{count: count}
+}
+
+func (b *block) add(label, action string, setup func(), op func(i, thread int)) {
+	b.addCount(label, action, 0, setup, op)
+}
+
+// addCount adds a run that does count ops instead of the block's.
+func (b *block) addCount(label, action string, count int, setup func(), op func(i, thread int)) {
+	b.runs = append(b.runs, run{label: label, action: action, count: count, setup: setup, op: op})
+}
+
+func (b *block) exec() {
+	runs := append([]run(nil), b.runs...)
+	if shuffleRuns {
+		rng.Shuffle(len(runs), func(i, j int) {
+			runs[i], runs[j] = runs[j], runs[i]
+		})
+	}
+	for _, r := range runs {
+		count := b.count
+		if r.count > 0 {
+			count = r.count
+		}
+		if coldMode {
+			r.prepare()
+			evictCaches()
+			cold := benchOps(r.label, r.action+" (cold)", count, 1, r.op)
+			r.prepare()
+			r.warmup(count, warmupOps)
+			warm := benchOps(r.label, r.action+" (warm)", count, 1, r.op)
+			coldWarm = append(coldWarm, coldWarmResult{
+				label: r.label, action: r.action, cold: cold, warm: warm,
+			})
+			b.record(r, warm)
+			continue
+		}
+		r.prepare()
+		if warmupOps > 0 {
+			r.warmup(count, warmupOps)
+		}
+		b.record(r, benchOps(r.label, r.action, count, 1, r.op))
+	}
+	// The runs hold the structures being measured; keep them
+	// reachable until the last one has reported its memory.
+	runtime.KeepAlive(runs)
+}
+
+func (b *block) record(r run, res result) {
+	if b.results == nil {
+		b.results = make(map[runKey]result)
+	}
+	b.results[runKey{r.label, r.action}] = res
+}
+
+// result returns what the run with this label and action measured
+// (the warm run for -cold), once exec has run it.
+func (b *block) result(label, action string) (result, bool) {
+	res, ok := b.results[runKey{label, action}]
+	return res, ok
+}
+
+// printRatio prints how long run r took as a multiple of run base,
+// naming base as of. It prints nothing unless exec has run both.
+func (b *block) printRatio(r, base runKey, of string) {
+	rr, ok1 := b.result(r.label, r.action)
+	rb, ok2 := b.result(base.label, base.action)
+	if !ok1 || !ok2 || rb.nsop() == 0 {
+		return
+	}
+	print_label(r.label, r.action)
+	fmt.Printf("%.2fx the time of %s\n", rr.nsop()/rb.nsop(), of)
+}
+
+func (r run) prepare() {
+	if r.setup != nil {
+		r.setup()
+	}
+}
+
+// warmup does n untimed ops (the whole count when n is zero) and then
+// prepares the run again, so the timed ops start from the same state.
+func (r run) warmup(count, n int) {
+	if n <= 0 || n > count {
+		n = count
+	}
+	for i := 0; i < n; i++ {
+		r.op(i, 0)
+	}
+	r.prepare()
+}
+
+var coldBuf []byte
+
+// evictCaches writes to every cache line of a buffer much larger than
+// the caches, pushing out whatever the previous run left there.
+func evictCaches() {
+	if coldBuf == nil {
+		coldBuf = make([]byte, coldBufMB<<20)
+	}
+	for i := 0; i < len(coldBuf); i += 64 {
+		coldBuf[i]++
+	}
+}
+
+type coldWarmResult struct {
+	label  string
+	action string
+	cold   result
+	warm   result
+}
+
+var coldWarm []coldWarmResult
+
+// printColdWarm reports the -cold runs side by side, worst cold
+// penalty first.
+func printColdWarm() {
+	if len(coldWarm) == 0 {
+		return
+	}
+	all := append([]coldWarmResult(nil), coldWarm...)
+	ratio := func(c coldWarmResult) float64 {
+		if c.warm.nsop() == 0 {
+			return 0
+		}
+		return c.cold.nsop() / c.warm.nsop()
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		return ratio(all[i]) > ratio(all[j])
+	})
+	println()
+	println("** cold vs warm **")
+	fmt.Printf("%-24s %-17s %10s %10s %6s\n", "", "", "cold ns/op", "warm ns/op", "ratio")
+	for _, c := range all {
+		fmt.Printf("%-24s %-17s %10.0f %10.0f %6.2f\n", c.label+":", c.action,
+			c.cold.nsop(), c.warm.nsop(), ratio(c))
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// benchBoundedRanges times [lo, hi) range queries holding each of the
+// given lengths, starting at random pivots. Every contender is timed
+// with its native bounded API (bounded-*), where it has one, and with
+// an open-ended ascend that it stops itself (until-*), so the cost of
+// the upper bound check shows up as the difference.
+//
+// ents must already be in random order; the pivots are taken from it.
+func benchBoundedRanges(impls []impl, ents []entry, lengths []int) {
+	println()
+	println("** bounded range **")
+	println("Test visiting every item in [lo, hi) starting at a random pivot.")
+	for _, im := range impls {
+		_, native := im.(boundedRanger)
+		_, manual := im.(manualRanger)
+		if native || manual {
+			fill(im, ents)
+		}
+	}
+
+	sorted := append([]entry(nil), ents...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].item.key < sorted[j].item.key
+	})
+	rank := make(map[keyT]int, len(sorted))
+	for i, e := range sorted {
+		rank[e.item.key] = i
+	}
+
+	for _, length := range lengths {
+		if length == allLength {
+			continue
+		}
+		count := rangeOps(len(ents), length)
+
+		// hi is the key length places after lo, so [lo, hi) holds
+		// exactly length items. Pivots too close to the end are
+		// moved back so that there is always a hi.
+		los := make([]entry, count)
+		his := make([]entry, count)
+		want := make([]int, count)
+		for i := range los {
+			r := rank[ents[i].item.key]
+			if r+length >= len(sorted) {
+				r = len(sorted) - 1 - length
+			}
+			if r < 0 {
+				r = 0
+			}
+			los[i] = sorted[r]
+			hr := min(r+length, len(sorted)-1)
+			his[i] = sorted[hr]
+			want[i] = hr - r
+		}
+
+		for _, kind := range []string{"bounded", "until"} {
+			action := kind + "-" + lengthName(length)
+			b := newBlock(count)
+			for _, im := range impls {
+				var visit func(lo, hi entry, fn func() bool)
+				switch kind {
+				case "bounded":
+					if r, ok := im.(boundedRanger); ok {
+						visit = r.ascendRange
+					}
+				case "until":
+					if r, ok := im.(manualRanger); ok {
+						visit = r.ascendUntil
+					}
+				}
+				if visit == nil {
+					continue
+				}
+				n := count
+				if _, ok := im.(linearSeeker); ok && n > linearOps {
+					n = linearOps
+				}
+				b.addCount(im.name(), action, n, nil, func(i, _ int) {
+					var seen int
+					visit(los[i], his[i], func() bool {
+						seen++
+						return true
+					})
+					if seen != want[i] {
+						panic(fmt.Sprintf("%s: %s: saw %d items, want %d",
+							im.name(), action, seen, want[i]))
+					}
+				})
+			}
+			b.exec()
+		}
+	}
+}
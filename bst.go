@@ -0,0 +1,536 @@
+package main
+
+import "strings"
+
+// Two balanced binary search trees, written here so that the B-trees
+// can be compared with the classic pointer-per-item structure: one
+// item per node, and a cache miss per level on the way down.
+
+// bstNode is the node of both trees. red is only used by rbTree and
+// prio only by treap; both fit in the padding after the pointers.
+type bstNode[T any] struct {
+	item        T
+	left, right *bstNode[T]
+	red         bool
+	prio        uint32
+}
+
+// bstBase has what both trees share: the reads, which do not care how
+// the tree was balanced.
+type bstBase[T any] struct {
+	root  *bstNode[T]
+	less  func(a, b T) bool
+	count int
+}
+
+func (b *bstBase[T]) Len() int { return b.count }
+
+func (b *bstBase[T]) Get(item T) (T, bool) {
+	n := b.root
+	for n != nil {
+		switch {
+		case b.less(item, n.item):
+			n = n.left
+		case b.less(n.item, item):
+			n = n.right
+		default:
+			return n.item, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+func (b *bstBase[T]) Min() (T, bool) {
+	n := b.root
+	if n == nil {
+		var zero T
+		return zero, false
+	}
+	for n.left != nil {
+		n = n.left
+	}
+	return n.item, true
+}
+
+func (b *bstBase[T]) Max() (T, bool) {
+	n := b.root
+	if n == nil {
+		var zero T
+		return zero, false
+	}
+	for n.right != nil {
+		n = n.right
+	}
+	return n.item, true
+}
+
+// Ascend calls iter for every item >= pivot in ascending order, until
+// iter returns false.
+func (b *bstBase[T]) Ascend(pivot T, iter func(item T) bool) {
+	b.ascend(b.root, pivot, iter)
+}
+
+// Descend calls iter for every item <= pivot in descending order,
+// until iter returns false.
+func (b *bstBase[T]) Descend(pivot T, iter func(item T) bool) {
+	b.descend(b.root, pivot, iter)
+}
+
+func (b *bstBase[T]) ascend(n *bstNode[T], pivot T, iter func(item T) bool) bool {
+	if n == nil {
+		return true
+	}
+	if b.less(n.item, pivot) {
+		return b.ascend(n.right, pivot, iter)
+	}
+	// Everything right of n is past the pivot too.
+	return b.ascend(n.left, pivot, iter) && iter(n.item) && ascendAll(n.right, iter)
+}
+
+func (b *bstBase[T]) descend(n *bstNode[T], pivot T, iter func(item T) bool) bool {
+	if n == nil {
+		return true
+	}
+	if b.less(pivot, n.item) {
+		return b.descend(n.left, pivot, iter)
+	}
+	return b.descend(n.right, pivot, iter) && iter(n.item) && descendAll(n.left, iter)
+}
+
+func ascendAll[T any](n *bstNode[T], iter func(item T) bool) bool {
+	return n == nil || ascendAll(n.left, iter) && iter(n.item) && ascendAll(n.right, iter)
+}
+
+func descendAll[T any](n *bstNode[T], iter func(item T) bool) bool {
+	return n == nil || descendAll(n.right, iter) && iter(n.item) && descendAll(n.left, iter)
+}
+
+// rbTree is a left-leaning red-black tree, after Sedgewick's
+// Algorithms, 4th edition: a 2-3 tree whose 3-nodes are a black node
+// with a red left child.
+type rbTree[T any] struct {
+	bstBase[T]
+}
+
+func newRBTree[T any](less func(a, b T) bool) *rbTree[T] {
+	return &rbTree[T]{bstBase[T]{less: less}}
+}
+
+func isRed[T any](n *bstNode[T]) bool { return n != nil && n.red }
+
+func rotateLeft[T any](h *bstNode[T]) *bstNode[T] {
+	x := h.right
+	h.right = x.left
+	x.left = h
+	x.red = h.red
+	h.red = true
+	return x
+}
+
+func rotateRight[T any](h *bstNode[T]) *bstNode[T] {
+	x := h.left
+	h.left = x.right
+	x.right = h
+	x.red = h.red
+	h.red = true
+	return x
+}
+
+func flipColors[T any](h *bstNode[T]) {
+	h.red = !h.red
+	h.left.red = !h.left.red
+	h.right.red = !h.right.red
+}
+
+// balance restores the left-leaning invariants at h on the way back
+// up from an insert or delete.
+func balance[T any](h *bstNode[T]) *bstNode[T] {
+	if isRed(h.right) && !isRed(h.left) {
+		h = rotateLeft(h)
+	}
+	if isRed(h.left) && isRed(h.left.left) {
+		h = rotateRight(h)
+	}
+	if isRed(h.left) && isRed(h.right) {
+		flipColors(h)
+	}
+	return h
+}
+
+// moveRedLeft makes h.left or one of its children red, given that h
+// is red and h.left and h.left.left are black, so that a delete can go
+// left without taking the last key out of a 2-node.
+func moveRedLeft[T any](h *bstNode[T]) *bstNode[T] {
+	flipColors(h)
+	if isRed(h.right.left) {
+		h.right = rotateRight(h.right)
+		h = rotateLeft(h)
+		flipColors(h)
+	}
+	return h
+}
+
+func moveRedRight[T any](h *bstNode[T]) *bstNode[T] {
+	flipColors(h)
+	if isRed(h.left.left) {
+		h = rotateRight(h)
+		flipColors(h)
+	}
+	return h
+}
+
+// Set inserts item, or replaces the item equal to it.
+func (t *rbTree[T]) Set(item T) {
+	t.root = t.insert(t.root, item)
+	t.root.red = false
+}
+
+func (t *rbTree[T]) insert(h *bstNode[T], item T) *bstNode[T] {
+	if h == nil {
+		t.count++
+		return &bstNode[T]{item: item, red: true}
+	}
+	switch {
+	case t.less(item, h.item):
+		h.left = t.insert(h.left, item)
+	case t.less(h.item, item):
+		h.right = t.insert(h.right, item)
+	default:
+		h.item = item
+	}
+	return balance(h)
+}
+
+// The deletes start by making the root red if both its children are
+// black, as the paths down assume h is red or has a red child.
+
+func (t *rbTree[T]) redRoot() {
+	if !isRed(t.root.left) && !isRed(t.root.right) {
+		t.root.red = true
+	}
+}
+
+func (t *rbTree[T]) blackRoot() {
+	if t.root != nil {
+		t.root.red = false
+	}
+}
+
+// Delete removes the item equal to item, and reports whether there
+// was one.
+func (t *rbTree[T]) Delete(item T) bool {
+	if t.root == nil {
+		return false
+	}
+	t.redRoot()
+	var ok bool
+	t.root, ok = t.delete(t.root, item)
+	t.blackRoot()
+	if ok {
+		t.count--
+	}
+	return ok
+}
+
+func (t *rbTree[T]) delete(h *bstNode[T], item T) (*bstNode[T], bool) {
+	var ok bool
+	if t.less(item, h.item) {
+		if h.left == nil {
+			return h, false
+		}
+		if !isRed(h.left) && !isRed(h.left.left) {
+			h = moveRedLeft(h)
+		}
+		h.left, ok = t.delete(h.left, item)
+		return balance(h), ok
+	}
+	if isRed(h.left) {
+		h = rotateRight(h)
+	}
+	if h.right == nil {
+		if t.less(h.item, item) {
+			return balance(h), false
+		}
+		return nil, true
+	}
+	if !isRed(h.right) && !isRed(h.right.left) {
+		h = moveRedRight(h)
+	}
+	if !t.less(h.item, item) {
+		h.right, h.item = deleteMin(h.right)
+		ok = true
+	} else {
+		h.right, ok = t.delete(h.right, item)
+	}
+	return balance(h), ok
+}
+
+// DeleteMin removes and returns the smallest item.
+func (t *rbTree[T]) DeleteMin() (T, bool) {
+	var item T
+	if t.root == nil {
+		return item, false
+	}
+	t.redRoot()
+	t.root, item = deleteMin(t.root)
+	t.blackRoot()
+	t.count--
+	return item, true
+}
+
+// DeleteMax removes and returns the largest item.
+func (t *rbTree[T]) DeleteMax() (T, bool) {
+	var item T
+	if t.root == nil {
+		return item, false
+	}
+	t.redRoot()
+	t.root, item = deleteMax(t.root)
+	t.blackRoot()
+	t.count--
+	return item, true
+}
+
+func deleteMin[T any](h *bstNode[T]) (*bstNode[T], T) {
+	if h.left == nil {
+		return nil, h.item
+	}
+	if !isRed(h.left) && !isRed(h.left.left) {
+		h = moveRedLeft(h)
+	}
+	var item T
+	h.left, item = deleteMin(h.left)
+	return balance(h), item
+}
+
+func deleteMax[T any](h *bstNode[T]) (*bstNode[T], T) {
+	if isRed(h.left) {
+		h = rotateRight(h)
+	}
+	if h.right == nil {
+		return nil, h.item
+	}
+	if !isRed(h.right) && !isRed(h.right.left) {
+		h = moveRedRight(h)
+	}
+	var item T
+	h.right, item = deleteMax(h.right)
+	return balance(h), item
+}
+
+// treap is a binary search tree on the items and a heap on random
+// node priorities, which keeps it balanced in expectation. A node is
+// rotated up past any parent of lower priority on insert, and a
+// deleted node's children are merged by priority.
+type treap[T any] struct {
+	bstBase[T]
+	seed uint32
+}
+
+func newTreap[T any](less func(a, b T) bool) *treap[T] {
+	return &treap[T]{bstBase: bstBase[T]{less: less}, seed: 2463534242}
+}
+
+// prio is xorshift32, so that the priorities cost no lock or
+// allocation, and are the same from run to run.
+func (t *treap[T]) prio() uint32 {
+	t.seed ^= t.seed << 13
+	t.seed ^= t.seed >> 17
+	t.seed ^= t.seed << 5
+	return t.seed
+}
+
+// Set inserts item, or replaces the item equal to it.
+func (t *treap[T]) Set(item T) {
+	t.root = t.insert(t.root, item)
+}
+
+func (t *treap[T]) insert(n *bstNode[T], item T) *bstNode[T] {
+	if n == nil {
+		t.count++
+		return &bstNode[T]{item: item, prio: t.prio()}
+	}
+	switch {
+	case t.less(item, n.item):
+		n.left = t.insert(n.left, item)
+		if n.left.prio > n.prio {
+			l := n.left
+			n.left, l.right = l.right, n
+			return l
+		}
+	case t.less(n.item, item):
+		n.right = t.insert(n.right, item)
+		if n.right.prio > n.prio {
+			r := n.right
+			n.right, r.left = r.left, n
+			return r
+		}
+	default:
+		n.item = item
+	}
+	return n
+}
+
+// Delete removes the item equal to item, and reports whether there
+// was one.
+func (t *treap[T]) Delete(item T) bool {
+	link := &t.root
+	for n := *link; n != nil; n = *link {
+		switch {
+		case t.less(item, n.item):
+			link = &n.left
+		case t.less(n.item, item):
+			link = &n.right
+		default:
+			*link = merge(n.left, n.right)
+			t.count--
+			return true
+		}
+	}
+	return false
+}
+
+// merge joins a and b, all of whose items are less than those of b,
+// keeping the higher priority on top.
+func merge[T any](a, b *bstNode[T]) *bstNode[T] {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	case a.prio > b.prio:
+		a.right = merge(a.right, b)
+		return a
+	default:
+		b.left = merge(a, b.left)
+		return b
+	}
+}
+
+// DeleteMin removes and returns the smallest item.
+func (t *treap[T]) DeleteMin() (T, bool) {
+	link := &t.root
+	if *link == nil {
+		var zero T
+		return zero, false
+	}
+	for (*link).left != nil {
+		link = &(*link).left
+	}
+	n := *link
+	*link = n.right
+	t.count--
+	return n.item, true
+}
+
+// DeleteMax removes and returns the largest item.
+func (t *treap[T]) DeleteMax() (T, bool) {
+	link := &t.root
+	if *link == nil {
+		var zero T
+		return zero, false
+	}
+	for (*link).right != nil {
+		link = &(*link).right
+	}
+	n := *link
+	*link = n.left
+	t.count--
+	return n.item, true
+}
+
+// bst adapter: one type for both trees. Writes go through the
+// binaryTree interface; reads go to the shared bstBase directly.
+
+type binaryTree interface {
+	Set(item itemT)
+	Delete(item itemT) bool
+	DeleteMin() (itemT, bool)
+	DeleteMax() (itemT, bool)
+	base() *bstBase[itemT]
+}
+
+func (b *bstBase[T]) base() *bstBase[T] { return b }
+
+type bstImpl struct {
+	label string
+	fresh func() binaryTree
+	tr    binaryTree
+	b     *bstBase[itemT]
+}
+
+func newRBImpl() *bstImpl {
+	return &bstImpl{label: "red-black", fresh: func() binaryTree { return newRBTree(lessG) }}
+}
+
+func newTreapImpl() *bstImpl {
+	return &bstImpl{label: "treap", fresh: func() binaryTree { return newTreap(lessG) }}
+}
+
+func (t *bstImpl) name() string { return t.label }
+func (t *bstImpl) reset() {
+	t.tr = t.fresh()
+	t.b = t.tr.base()
+}
+func (t *bstImpl) set(e entry) { t.tr.Set(e.item) }
+func (t *bstImpl) get(e entry) bool {
+	_, ok := t.b.Get(e.item)
+	return ok
+}
+func (t *bstImpl) del(e entry) bool { return t.tr.Delete(e.item) }
+func (t *bstImpl) len() int         { return t.b.Len() }
+
+func (t *bstImpl) ascend(from entry, fn func() bool) {
+	t.b.Ascend(from.item, func(itemT) bool {
+		return fn()
+	})
+}
+
+func (t *bstImpl) descend(from entry, fn func() bool) {
+	t.b.Descend(from.item, func(itemT) bool {
+		return fn()
+	})
+}
+
+func (t *bstImpl) ascendRange(lo, hi entry, fn func() bool) {
+	t.b.Ascend(lo.item, func(item itemT) bool {
+		return item.key < hi.item.key && fn()
+	})
+}
+
+func (t *bstImpl) descendRange(lo, hi entry, fn func() bool) {
+	t.b.Descend(hi.item, func(item itemT) bool {
+		return item.key > lo.item.key && fn()
+	})
+}
+
+func (t *bstImpl) ascendUntil(lo, hi entry, fn func() bool) {
+	t.ascendRange(lo, hi, fn)
+}
+
+func (t *bstImpl) scanPrefix(prefix entry, fn func() bool) {
+	t.b.Ascend(prefix.item, func(item itemT) bool {
+		return strings.HasPrefix(string(item.key), string(prefix.item.key)) && fn()
+	})
+}
+
+func (t *bstImpl) peekMin(want entry) bool {
+	item, ok := t.b.Min()
+	return ok && item.key == want.item.key
+}
+
+func (t *bstImpl) popMin(want entry) bool {
+	item, ok := t.tr.DeleteMin()
+	return ok && item.key == want.item.key
+}
+
+func (t *bstImpl) peekMax(want entry) bool {
+	item, ok := t.b.Max()
+	return ok && item.key == want.item.key
+}
+
+func (t *bstImpl) popMax(want entry) bool {
+	item, ok := t.tr.DeleteMax()
+	return ok && item.key == want.item.key
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestRBTree(t *testing.T) { checkImpl(t, newRBImpl()) }
+func TestTreap(t *testing.T)  { checkImpl(t, newTreapImpl()) }
+
+func lessInt(a, b int) bool { return a < b }
+
+// churnTree runs random sets and deletes, from anywhere and from both
+// ends, on tr, calling check after each one.
+func churnTree(t *testing.T, tr interface {
+	Set(item int)
+	Delete(item int) bool
+	DeleteMin() (int, bool)
+	DeleteMax() (int, bool)
+}, check func(op int)) {
+	t.Helper()
+	r := rand.New(rand.NewSource(1))
+	for op := 0; op < 5000; op++ {
+		switch k := r.Intn(300); r.Intn(8) {
+		case 0:
+			tr.DeleteMin()
+		case 1:
+			tr.DeleteMax()
+		case 2, 3:
+			tr.Delete(k)
+		default:
+			tr.Set(k)
+		}
+		check(op)
+	}
+}
+
+// checkOrdered checks that the keys under n are in [lo, hi), where a
+// nil bound is open, and returns how many there are.
+func checkOrdered(t *testing.T, n *bstNode[int], lo, hi *int) int {
+	t.Helper()
+	if n == nil {
+		return 0
+	}
+	if (lo != nil && n.item < *lo) || (hi != nil && n.item >= *hi) {
+		t.Fatalf("%d is out of order", n.item)
+	}
+	return 1 + checkOrdered(t, n.left, lo, &n.item) + checkOrdered(t, n.right, &n.item, hi)
+}
+
+// TestRBInvariants checks after every write that rbTree is a left-
+// leaning red-black tree: the root is black, no red link leans right,
+// no red node has a red left child, and every path from the root to a
+// leaf crosses as many black links.
+func TestRBInvariants(t *testing.T) {
+	tr := newRBTree(lessInt)
+	var blackHeight func(n *bstNode[int]) int
+	blackHeight = func(n *bstNode[int]) int {
+		if n == nil {
+			return 0
+		}
+		if isRed(n.right) {
+			t.Fatalf("red link leans right at %d", n.item)
+		}
+		if isRed(n) && isRed(n.left) {
+			t.Fatalf("two red links in a row at %d", n.item)
+		}
+		l, r := blackHeight(n.left), blackHeight(n.right)
+		if l != r {
+			t.Fatalf("black height %d on the left of %d, %d on the right", l, n.item, r)
+		}
+		if !n.red {
+			l++
+		}
+		return l
+	}
+	churnTree(t, tr, func(op int) {
+		if isRed(tr.root) {
+			t.Fatalf("op %d: red root", op)
+		}
+		blackHeight(tr.root)
+		if n := checkOrdered(t, tr.root, nil, nil); n != tr.Len() {
+			t.Fatalf("op %d: %d nodes, Len is %d", op, n, tr.Len())
+		}
+	})
+}
+
+// TestTreapInvariants checks after every write that no node of treap
+// has a higher priority than its parent.
+func TestTreapInvariants(t *testing.T) {
+	tr := newTreap(lessInt)
+	var heap func(n *bstNode[int])
+	heap = func(n *bstNode[int]) {
+		if n == nil {
+			return
+		}
+		for _, c := range []*bstNode[int]{n.left, n.right} {
+			if c != nil && c.prio > n.prio {
+				t.Fatalf("%d outranks its parent %d", c.item, n.item)
+			}
+		}
+		heap(n.left)
+		heap(n.right)
+	}
+	churnTree(t, tr, func(op int) {
+		heap(tr.root)
+		if n := checkOrdered(t, tr.root, nil, nil); n != tr.Len() {
+			t.Fatalf("op %d: %d nodes, Len is %d", op, n, tr.Len())
+		}
+	})
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/glycerine/uart"
+)
+
+// benchBulkLoad times building each structure from N keys that are
+// already sorted, in every way the libraries offer:
+//
+//   - set-sorted: the plain insert (google's ReplaceOrInsert, tidwall's
+//     Set, badger's Put into an arena sized up front, ...).
+//   - hint-sorted: tidwall's SetHint, carrying one PathHint along.
+//   - load-sorted: tidwall's Load, which appends to the last leaf.
+//   - leaf-sorted: uART's InsertLeaf with the leaves allocated
+//     beforehand, outside the timing.
+//
+// The bytes/op lotsa reports is the size of the finished structure
+// per key. Below each block, the leaf fill factor of the trees that
+// can report one, and how much of its arena the skiplist used.
+//
+// ents must be sorted.
+func benchBulkLoad(impls []impl, ents []entry) {
+	println()
+	println("** bulk load **")
+	println("Test building each structure from keys that are already sorted.")
+
+	leaves := make([]uart.Leaf, len(ents))
+	for _, action := range []string{"set-sorted", "hint-sorted", "load-sorted", "leaf-sorted"} {
+		b := newBlock(len(ents))
+		var built []impl
+		for _, im := range impls {
+			var op func(i, _ int)
+			switch action {
+			case "set-sorted":
+				op = func(i, _ int) { im.set(ents[i]) }
+			case "hint-sorted":
+				if h, ok := im.(hintSetter); ok {
+					op = func(i, _ int) { h.setHint(ents[i]) }
+				}
+			case "load-sorted":
+				if l, ok := im.(loader); ok {
+					op = func(i, _ int) { l.load(ents[i]) }
+				}
+			case "leaf-sorted":
+				if l, ok := im.(leafInserter); ok {
+					op = func(i, _ int) { l.insertLeaf(&leaves[i]) }
+				}
+			}
+			if op == nil {
+				continue
+			}
+			setup := im.reset
+			if action == "leaf-sorted" {
+				setup = func() {
+					im.reset()
+					for i, e := range ents {
+						leaves[i] = uart.Leaf{Key: e.bkey, Value: e.item.val}
+					}
+				}
+			}
+			b.add(im.name(), action, setup, op)
+			built = append(built, im)
+		}
+		b.exec()
+
+		for _, im := range built {
+			if n := im.len(); n != len(ents) {
+				panic(fmt.Sprintf("%v: %v: %d keys, want %d", im.name(), action, n, len(ents)))
+			}
+			if f, ok := im.(leafFiller); ok {
+				print_label(im.name(), action)
+				fmt.Printf("leaves %.1f%% full\n", 100*f.leafFill())
+			}
+			if a, ok := im.(arenaUser); ok {
+				used, size := a.arena()
+				printArena(im.name(), action, used, size, len(ents))
+			}
+		}
+	}
+}
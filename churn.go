@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// -churn is how many times N ops each churn scenario runs for.
+var churnRounds = 10
+
+// benchChurn holds N keys and keeps replacing them, the way a long
+// running cache does, for churnRounds rounds of N deletes and N
+// inserts:
+//
+//   - window: a sliding window; insert a new key and delete the
+//     oldest one.
+//   - replace: delete a random key and insert a random new one.
+//
+// An op is one delete and one insert. Throughput and retained heap
+// are measured after every round, and shown for rounds 1, 2, 4, ...
+// and the last, followed by the drift from the first round to the
+// last. badger/skiplist deletes with tombstones and never frees its
+// arena, so it is sized for every key and write of every round. The
+// contenders whose writes cost O(n) do linearOps ops a round.
+//
+// ents must already be in random order. The new keys are generated
+// here and never collide with ents.
+func benchChurn(impls []impl, ents []entry, rounds int) {
+	println()
+	println("** churn **")
+	println("Test holding N keys while deleting and inserting, for many times N ops.")
+
+	n := len(ents)
+	pool := append(append([]entry(nil), ents...), freshEntries(ents, n)...)
+
+	// The replace positions are drawn once and replayed every round,
+	// so every contender does the same deletes and inserts.
+	dels := make([]int, n)
+	ins := make([]int, n)
+	for i := range dels {
+		dels[i] = rng.Intn(n)
+		ins[i] = n + rng.Intn(n)
+	}
+
+	for _, scenario := range []string{"window", "replace"} {
+		for _, im := range impls {
+			d, ok := im.(deleter)
+			if !ok {
+				continue
+			}
+			var op func(i int)
+			switch scenario {
+			case "window":
+				// The window is pool[t%2n:] for n keys; the key
+				// going in is always one of the n outside it.
+				t := 0
+				op = func(int) {
+					im.set(pool[(t+n)%len(pool)])
+					d.del(pool[t%len(pool)])
+					t++
+				}
+			case "replace":
+				// slots[:n] are in the structure, slots[n:] are not.
+				slots := append([]entry(nil), pool...)
+				op = func(i int) {
+					a, b := dels[i], ins[i]
+					d.del(slots[a])
+					im.set(slots[b])
+					slots[a], slots[b] = slots[b], slots[a]
+				}
+			}
+			reserveArena(im, len(pool), 2*rounds*writeOps(im, n, false))
+			churn(im, ents, scenario, rounds, op)
+		}
+	}
+}
+
+// churn fills im, runs rounds rounds of op and prints the report.
+func churn(im impl, ents []entry, scenario string, rounds int, op func(i int)) {
+	if traceMatch(im.name(), scenario) {
+		stop := startTrace(im.name(), scenario)
+		defer stop()
+	}
+	n := len(ents)
+	im.reset()
+	base := heapAlloc()
+	load(im, ents)
+	ops := writeOps(im, n, false)
+	nsop := make([]float64, rounds)
+	heap := make([]uint64, rounds)
+	for r := 0; r < rounds; r++ {
+		beforeTiming()
+		start := time.Now()
+		for i := 0; i < ops; i++ {
+			op(i)
+		}
+		nsop[r] = float64(time.Since(start).Nanoseconds()) / float64(ops)
+		if after := heapAlloc(); after > base {
+			heap[r] = after - base
+		}
+		if got := im.len(); got != n {
+			panic(fmt.Sprintf("%v: %v: round %d left %d keys, want %d",
+				im.name(), scenario, r+1, got, n))
+		}
+	}
+	for r := 0; r < rounds; r++ {
+		if r+1 == rounds || (r+1)&r == 0 {
+			print_label(im.name(), fmt.Sprintf("%s-%dN", scenario, r+1))
+			fmt.Printf("%.0f ns/op, heap %s\n", nsop[r], memString(heap[r]))
+		}
+	}
+	print_label(im.name(), scenario+"-drift")
+	fmt.Printf("%+.1f%% ns/op, %+.1f%% heap\n",
+		drift(nsop[0], nsop[rounds-1]), drift(float64(heap[0]), float64(heap[rounds-1])))
+	// An arena is allocated before the heap is measured, so what its
+	// tombstones hold on to shows here instead.
+	if a, ok := im.(arenaUser); ok {
+		used, size := a.arena()
+		printArena(im.name(), scenario+"-arena", used, size, n)
+	}
+}
+
+// drift is the change from first to last in percent.
+func drift(first, last float64) float64 {
+	if first == 0 {
+		return 0
+	}
+	return 100 * (last - first) / first
+}
+
+// freshEntries returns n random keys, of the kind picked by -keys like
+// ents, that are not in ents.
+func freshEntries(ents []entry, n int) []entry {
+	seen := make(map[keyT]bool, len(ents)+n)
+	for _, e := range ents {
+		seen[e.item.key] = true
+	}
+	fresh := make([]entry, 0, n)
+	for len(fresh) < n {
+		item := newItem(rng.Int63n(keySpace))
+		if seen[item.key] {
+			continue
+		}
+		seen[item.key] = true
+		fresh = append(fresh, entry{item: item, bkey: []byte(item.key)})
+	}
+	return fresh
+}
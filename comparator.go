@@ -0,0 +1,178 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"math"
+	"strings"
+
+	gbtree "github.com/google/btree"
+)
+
+// compareSink keeps the comparisons from being optimized away.
+var compareSink int
+
+// compareWindow is how many keys the comparators go around, few
+// enough to stay in cache, so that a comparison costs only itself.
+// Cache misses on the way down a tree belong to its node layout.
+const compareWindow = 1024
+
+// comparatorOf names the comparison each contender makes on the way
+// down, as timed by benchComparators. uART and the Go map do not
+// compare keys, and are left out. So are the skiplists: how many more
+// than log2(N) comparisons they make depends on the heights their
+// nodes drew, so a log2(N) share would be wrong for them.
+var comparatorOf = map[string]string{
+	"google":                  "Item.Less",
+	"google(G)":               "lessG",
+	"google(K)":               "func(keyT)",
+	"tidwall":                 "less(any)",
+	"tidwall(G)":              "lessG",
+	"tidwall(G) with locking": "lessG",
+	"tidwall(M)":              "keyT <",
+	"tidwall(S)":              "keyT <",
+	"google(bytes)":           "lessB",
+	"tidwall(bytes)":          "lessB",
+	"sorted slice":            "cmp.Compare",
+	"red-black":               "lessG",
+	"treap":                   "lessG",
+}
+
+// benchComparators times each comparator alone, N times, on pairs of
+// neighbouring keys from the first compareWindow keys of ents, so that
+// how much of a tree's time is the comparator and how much the rest
+// can be told apart:
+//
+//   - less(any): the non-generic tidwall's less, on items boxed in
+//     interfaces, with its two type assertions.
+//   - Item.Less: the non-generic google's interface method, one type
+//     assertion.
+//   - lessG: the generic trees' func on itemT.
+//   - lessB: the same on bitemT, which calls bytes.Compare.
+//   - func(keyT): the same on bare keys, as for google(K).
+//   - keyT <: the operator, which tidwall's Map and Set compile in.
+//   - bytes.Compare, strings.Compare, cmp.Compare: for the []byte
+//     keyed contenders, and the three-way compares for reference.
+//
+// The funcs are called through func values, as the trees hold them.
+//
+// Then each contender in comparatorOf has its get-rand timed again,
+// shown next to an estimate of the comparator's share of it: log2(N)
+// comparisons, which is about what a binary search down any balanced
+// tree takes. The comparisons are not counted, so the share, and the
+// time without it, are estimates.
+//
+// ents must already be in random order.
+func benchComparators(impls []impl, ents []entry) {
+	println()
+	println("** comparators **")
+	println("Test each comparator alone, on the same key pairs.")
+
+	// Op i compares key at[i] with key at[i]+1.
+	n := len(ents)
+	if n < 2 {
+		return
+	}
+	w := min(n, compareWindow)
+	at := make([]int, n)
+	for i := range at {
+		at[i] = i % (w - 1)
+	}
+	keys := ents[:w]
+	boxed := make([]any, w)
+	gitems := make([]gbtree.Item, w)
+	bitems := make([]bitemT, w)
+	for i, e := range keys {
+		boxed[i] = e.item
+		gitems[i] = e.item
+		bitems[i] = bitem(e)
+	}
+	lessAny := less
+	lessItem := lessG
+	lessBytes := lessB
+	lessKey := func(a, b keyT) bool { return a < b }
+	byteCompare := bytes.Compare
+	stringCompare := strings.Compare
+	keyCompare := cmp.Compare[keyT]
+
+	b := newBlock(n)
+	b.add("less(any)", "compare", nil, func(i, _ int) {
+		if lessAny(boxed[at[i]], boxed[at[i]+1]) {
+			compareSink++
+		}
+	})
+	b.add("Item.Less", "compare", nil, func(i, _ int) {
+		if gitems[at[i]].Less(gitems[at[i]+1]) {
+			compareSink++
+		}
+	})
+	b.add("lessG", "compare", nil, func(i, _ int) {
+		if lessItem(keys[at[i]].item, keys[at[i]+1].item) {
+			compareSink++
+		}
+	})
+	b.add("lessB", "compare", nil, func(i, _ int) {
+		if lessBytes(bitems[at[i]], bitems[at[i]+1]) {
+			compareSink++
+		}
+	})
+	b.add("func(keyT)", "compare", nil, func(i, _ int) {
+		if lessKey(keys[at[i]].item.key, keys[at[i]+1].item.key) {
+			compareSink++
+		}
+	})
+	b.add("keyT <", "compare", nil, func(i, _ int) {
+		if keys[at[i]].item.key < keys[at[i]+1].item.key {
+			compareSink++
+		}
+	})
+	b.add("bytes.Compare", "compare", nil, func(i, _ int) {
+		if byteCompare(keys[at[i]].bkey, keys[at[i]+1].bkey) < 0 {
+			compareSink++
+		}
+	})
+	b.add("strings.Compare", "compare", nil, func(i, _ int) {
+		if stringCompare(string(keys[at[i]].item.key), string(keys[at[i]+1].item.key)) < 0 {
+			compareSink++
+		}
+	})
+	b.add("cmp.Compare", "compare", nil, func(i, _ int) {
+		if keyCompare(keys[at[i]].item.key, keys[at[i]+1].item.key) < 0 {
+			compareSink++
+		}
+	})
+	b.exec()
+
+	println()
+	println("** comparator share **")
+	fmt.Printf("Test get-rand again, next to an estimate of the comparator's share: log2(N) = %.1f comparisons.\n", math.Log2(float64(n)))
+	gets := newBlock(n)
+	var timed []impl
+	for _, im := range impls {
+		if _, ok := comparatorOf[im.name()]; !ok || !fits(im, ents) {
+			continue
+		}
+		gets.add(im.name(), "get-rand", func() { fill(im, ents) }, func(i, _ int) {
+			if !im.get(ents[i]) {
+				panic(fmt.Sprintf("%v: %q not found", im.name(), ents[i].item.key))
+			}
+		})
+		timed = append(timed, im)
+	}
+	gets.exec()
+
+	println()
+	for _, im := range timed {
+		c := comparatorOf[im.name()]
+		get, ok1 := gets.result(im.name(), "get-rand")
+		one, ok2 := b.result(c, "compare")
+		if !ok1 || !ok2 || get.nsop() == 0 {
+			continue
+		}
+		share := one.nsop() * math.Log2(float64(n))
+		print_label(im.name(), "get-rand")
+		fmt.Printf("%.0f ns/op, est. %.0f of it %s (%.1f%%), est. %.0f without\n",
+			get.nsop(), share, c, 100*share/get.nsop(), max(0, get.nsop()-share))
+	}
+}
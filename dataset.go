@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// -seed seeds rng, and with it the keys, the shuffles and every other
+// random draw, so that a run can be repeated exactly. 0 picks a seed
+// from the clock; the report shows it either way. With -load-keys and
+// no -seed, the seed comes from the file.
+var seed int64
+
+// -dump-keys writes the keys to a file, and -load-keys reads them back
+// instead of making new ones.
+var dumpKeysFile string
+var loadKeysFile string
+
+// rng is the one source of randomness in the benchmark. It is not safe
+// for concurrent use, so only the main goroutine draws from it.
+var rng *rand.Rand
+
+// keyRNG makes the keys. It is seeded with rng's first draw, so that
+// a run with -load-keys, which makes none, goes on to draw the same
+// shuffles and distributions from rng as the run that dumped them.
+var keyRNG *rand.Rand
+
+// seedRNG seeds rng with s, first picking one if it is 0, and keeps
+// it in seed for the report.
+func seedRNG(s int64) {
+	if s == 0 {
+		s = time.Now().UnixNano()
+	}
+	seed = s
+	rng = rand.New(rand.NewSource(seed))
+	keyRNG = rand.New(rand.NewSource(rng.Int63()))
+}
+
+// makeItems makes n items with different random keys, of the kind
+// picked by -keys.
+func makeItems(n int) []itemT {
+	items := make([]itemT, n)
+	seen := make(map[int64]bool, n)
+	for i := range items {
+		for {
+			key := keyRNG.Int63n(keySpace)
+			if !seen[key] {
+				seen[key] = true
+				items[i] = newItem(key)
+				break
+			}
+		}
+	}
+	return items
+}
+
+// writeKeys writes items to fn for -dump-keys: a header line with the
+// seed and the key flags, then one item per line, its value and its
+// key.
+func writeKeys(fn string, items []itemT) error {
+	f, err := os.Create(fn)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	fmt.Fprintf(w, "# seed %d -keys %s -commonprefix %d\n", seed, keyKind, commonPrefixLen)
+	for _, item := range items {
+		fmt.Fprintf(w, "%d %s\n", item.val, item.key)
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// readKeys reads back a file written by writeKeys for -load-keys, and
+// the seed they were made with. The key flags in its header take over
+// from the command line's, so that keys made during the run, like the
+// churn's fresh keys, are of the same kind. The keys must all be
+// different and of one length.
+func readKeys(fn string) (items []itemT, fileSeed int64, err error) {
+	f, err := os.Open(fn)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	if !sc.Scan() {
+		if err := sc.Err(); err != nil {
+			return nil, 0, err
+		}
+		return nil, 0, fmt.Errorf("%s: empty", fn)
+	}
+	if _, err := fmt.Sscanf(sc.Text(), "# seed %d -keys %s -commonprefix %d",
+		&fileSeed, &keyKind, &commonPrefixLen); err != nil {
+		return nil, 0, fmt.Errorf("%s: bad header %q: %w", fn, sc.Text(), err)
+	}
+	if err := setKeyKind(); err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", fn, err)
+	}
+
+	seen := make(map[keyT]bool)
+	for line := 2; sc.Scan(); line++ {
+		v, k, ok := strings.Cut(sc.Text(), " ")
+		val, err := strconv.ParseInt(v, 10, 64)
+		if !ok || err != nil {
+			return nil, 0, fmt.Errorf("%s:%d: bad item %q", fn, line, sc.Text())
+		}
+		key := keyT(k)
+		if len(key) != keyLen {
+			return nil, 0, fmt.Errorf("%s:%d: key %q is %d bytes, want %d", fn, line, key, len(key), keyLen)
+		}
+		if seen[key] {
+			return nil, 0, fmt.Errorf("%s:%d: duplicate key %q", fn, line, key)
+		}
+		seen[key] = true
+		items = append(items, itemT{key: key, val: valT(val)})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, 0, err
+	}
+	if len(items) == 0 {
+		return nil, 0, fmt.Errorf("%s: no keys", fn)
+	}
+	return items, fileSeed, nil
+}
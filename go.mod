@@ -8,12 +8,15 @@ require (
 	github.com/tidwall/lotsa v1.0.3
 )
 
-require github.com/glycerine/uart v0.12.7
+require (
+	github.com/dgraph-io/badger/v3 v3.2103.5
+	github.com/glycerine/uart v0.12.7
+	github.com/zhangyunhao116/skipmap v0.10.1
+)
 
 require (
 	github.com/cespare/xxhash v1.1.0 // indirect
 	github.com/cespare/xxhash/v2 v2.1.1 // indirect
-	github.com/dgraph-io/badger/v3 v3.2103.5 // indirect
 	github.com/dgraph-io/ristretto v0.1.1 // indirect
 	github.com/dustin/go-humanize v1.0.0 // indirect
 	github.com/gogo/protobuf v1.3.2 // indirect
@@ -23,7 +26,6 @@ require (
 	github.com/klauspost/compress v1.12.3 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
 	github.com/zhangyunhao116/fastrand v0.3.0 // indirect
-	github.com/zhangyunhao116/skipmap v0.10.1 // indirect
 	golang.org/x/exp v0.0.0-20221217163422-3c43f8badb15 // indirect
 	golang.org/x/net v0.4.0 // indirect
 	golang.org/x/sys v0.3.0 // indirect
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// benchIndex times positional access: the key at an index in sorted
+// order (getat), deleting by index (deleteat), and the index of a key
+// (rank). Contenders with an indexer do it natively; the others are
+// emulated by walking an iterator (the *-scan actions), which costs
+// O(n), so those get linearOps ops:
+//
+//   - getat-scan ascends from the smallest key for i+1 keys.
+//   - deleteat-scan does the same walk and then deletes the key,
+//     which the walk has no way to hand back, so it comes from the
+//     test's own sorted copy.
+//   - rank-scan ascends from the key to the end, and subtracts that
+//     count from the length.
+//
+// ents must already be in random order.
+func benchIndex(impls []impl, ents []entry) {
+	println()
+	println("** index **")
+	println("Test getting and deleting by index in sorted order, and the rank of a key.")
+
+	n := len(ents)
+	sorted := append([]entry(nil), ents...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].item.key < sorted[j].item.key
+	})
+	rank := make(map[keyT]int, n)
+	for i, e := range sorted {
+		rank[e.item.key] = i
+	}
+	first := sorted[0]
+
+	// at[i] is the index of ents[i] in the sorted keys; delAt[i] is its
+	// index once ents[:i] have been deleted.
+	at := make([]int, n)
+	for i, e := range ents {
+		at[i] = rank[e.item.key]
+	}
+	delAt := deleteIndexes(at)
+
+	walkTo := func(a ascender, i int) {
+		var seen int
+		a.ascend(first, func() bool {
+			seen++
+			return seen <= i
+		})
+	}
+
+	for _, action := range []string{"getat", "deleteat", "rank"} {
+		b := newBlock(n)
+		for _, im := range impls {
+			ix, native := im.(indexer)
+			a, ok := im.(ascender)
+			if !native && !ok {
+				continue
+			}
+			d, canDel := im.(deleter)
+			if action == "deleteat" && !native && !canDel {
+				continue
+			}
+			setup := func() { fill(im, ents) }
+			if !native {
+				count := min(n, linearOps)
+				switch action {
+				case "getat":
+					b.addCount(im.name(), action+"-scan", count, setup, func(i, _ int) {
+						walkTo(a, at[i])
+					})
+				case "deleteat":
+					b.addCount(im.name(), action+"-scan", count, setup, func(i, _ int) {
+						walkTo(a, delAt[i])
+						d.del(ents[i])
+					})
+				case "rank":
+					b.addCount(im.name(), action+"-scan", count, setup, func(i, _ int) {
+						var seen int
+						a.ascend(ents[i], func() bool {
+							seen++
+							return true
+						})
+						if r := n - seen; r != at[i] {
+							panic(fmt.Sprintf("%v: rank %d, want %d", im.name(), r, at[i]))
+						}
+					})
+				}
+				continue
+			}
+			switch action {
+			case "getat":
+				b.add(im.name(), action, setup, func(i, _ int) {
+					if !ix.getAt(at[i], ents[i]) {
+						panic(fmt.Sprintf("%v: index %d is not %q", im.name(), at[i], ents[i].bkey))
+					}
+				})
+			case "deleteat":
+				b.addCount(im.name(), action, writeOps(im, n, true), setup, func(i, _ int) {
+					if !ix.deleteAt(delAt[i], ents[i]) {
+						panic(fmt.Sprintf("%v: index %d is not %q", im.name(), delAt[i], ents[i].bkey))
+					}
+				})
+			case "rank":
+				b.add(im.name(), action, setup, func(i, _ int) {
+					if r := ix.rank(ents[i]); r != at[i] {
+						panic(fmt.Sprintf("%v: rank %d, want %d", im.name(), r, at[i]))
+					}
+				})
+			}
+		}
+		b.exec()
+	}
+}
+
+// deleteIndexes takes the sorted indexes of keys deleted in the given
+// order, and returns the index each one has at the time it is deleted:
+// its index less the number of smaller keys deleted before it. A
+// Fenwick tree over the indexes keeps that count in O(log n).
+func deleteIndexes(at []int) []int {
+	tree := make([]int, len(at)+1)
+	out := make([]int, len(at))
+	for i, idx := range at {
+		var gone int
+		for j := idx; j > 0; j -= j & -j {
+			gone += tree[j]
+		}
+		out[i] = idx - gone
+		for j := idx + 1; j < len(tree); j += j & -j {
+			tree[j]++
+		}
+	}
+	return out
+}
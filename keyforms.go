@@ -0,0 +1,35 @@
+package main
+
+// keyFormTwins pairs each contender keyed by the other form with the
+// same structure on its own: the []byte twins of the string-keyed
+// B-trees, and the string-fed twins of the []byte-keyed uART and
+// skipmap.
+var keyFormTwins = [][2]string{
+	{"google(bytes)", "google(G)"},
+	{"tidwall(bytes)", "tidwall(G)"},
+	{"zhangyunhao116/skipmap(str)", "zhangyunhao116/skipmap"},
+	{"uART(str)", "uART"},
+}
+
+// benchKeyForms runs set-rand and get-rand on each contender and its
+// twin in the other key form, then shows the twin's time as a multiple
+// of the original's, so that every structure can be compared on both
+// string and []byte keys.
+//
+// ents must already be in random order.
+func benchKeyForms(impls []impl, ents []entry) {
+	println()
+	println("** key forms **")
+	println("Test each structure on string keys and on []byte keys.")
+
+	twins := twinsOf(impls, keyFormTwins)
+	actions := []string{"set-rand", "get-rand"}
+	blocks := benchTwins(twins, ents, actions)
+
+	println()
+	println("** other key form vs own **")
+	println("How long each structure takes on the other key form, compared with its own.")
+	for _, t := range twins {
+		printTwinRatios(t, blocks, actions)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import "fmt"
+
+// keyOnlyTwins pairs each key-only tree with the key/value tree of the
+// same library.
+var keyOnlyTwins = [][2]string{
+	{"tidwall(S)", "tidwall(M)"},
+	{"google(K)", "google(G)"},
+}
+
+// benchKeyOnly puts each key-only tree next to its key/value twin:
+// set-rand, get-rand and a scan of every key, shown per key too, then
+// the heap each holds per key once full. The block ends with each
+// key-only tree's time and memory as a multiple of its twin's.
+//
+// ents must already be in random order.
+func benchKeyOnly(impls []impl, ents []entry) {
+	println()
+	println("** key-only **")
+	println("Test key-only trees next to the key/value trees of the same library.")
+
+	n := len(ents)
+	twins := twinsOf(impls, keyOnlyTwins)
+	actions := []string{"set-rand", "get-rand", "scan"}
+	blocks := benchTwins(twins, ents, actions)
+	for _, t := range twins {
+		for _, im := range t {
+			if r, ok := blocks["scan"].result(im.name(), "scan"); ok {
+				print_label(im.name(), "scan")
+				fmt.Printf("%.1f ns/key\n", r.nsop()/float64(n))
+			}
+		}
+	}
+
+	perKey := make(map[string]float64, 2*len(twins))
+	for _, t := range twins {
+		for _, im := range t {
+			perKey[im.name()] = float64(fillSize(im, ents)) / float64(n)
+			print_label(im.name(), "heap")
+			fmt.Printf("%.1f bytes/key\n", perKey[im.name()])
+		}
+	}
+
+	println()
+	println("** key-only vs key/value **")
+	println("How long each key-only tree takes, and how much it holds, compared with its twin.")
+	for _, t := range twins {
+		printTwinRatios(t, blocks, actions)
+		k, kv := t[0].name(), t[1].name()
+		if perKey[kv] > 0 {
+			print_label(k, "heap")
+			fmt.Printf("%.2fx the bytes/key of %s\n", perKey[k]/perKey[kv], kv)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// -keys picks the kind of keys, from keyKinds, and -commonprefix how
+// many bytes the prefix kind puts in front of every key.
+var keyKind = "num"
+var commonPrefixLen = 16
+
+// keySpace is how many different keys each kind can make. Random keys
+// are drawn as numbers below it and handed to makeKey.
+const keySpace = 10000000000000000
+
+// keyTables are the tables of the path keys and the sections of the
+// URL keys, keyHosts the hosts of the URL keys. The names in each are
+// all the same length.
+var keyTables = []string{"users", "posts", "items", "carts", "media", "pages", "feeds", "flags"}
+var keyHosts = []string{"api.example.com", "www.example.com", "cdn.example.com", "img.example.com"}
+
+// keyKinds makes a key of each kind from a number below keySpace, a
+// different key for each number:
+//
+//   - num: the number in 16 decimal digits. Random keys share little
+//     more than their first digit or two.
+//   - path: "tenant-042/items/0004882812500", 256 tenants of 8 tables.
+//     Keys share 17 bytes with the rest of their table.
+//   - url: "https://api.example.com/users/000312500000000", 4 hosts
+//     of 8 sections. Keys share 30 bytes with the rest of their section.
+//   - prefix: the num key behind the same -commonprefix bytes.
+//
+// Every kind makes keys of one length. badger takes the last 8 bytes
+// of a key as its version, so keys of different lengths would not sort
+// the same there as they do everywhere else.
+var keyKinds = map[string]func(n int64) keyT{
+	"num": func(n int64) keyT {
+		return keyT(fmt.Sprintf("%016d", n))
+	},
+	"path": func(n int64) keyT {
+		return keyT(fmt.Sprintf("tenant-%03d/%s/%013d",
+			n%256, keyTables[n/256%8], n/2048))
+	},
+	"url": func(n int64) keyT {
+		return keyT(fmt.Sprintf("https://%s/%s/%015d",
+			keyHosts[n%4], keyTables[n/4%8], n/32))
+	},
+	"prefix": func(n int64) keyT {
+		return keyT(fmt.Sprintf("%s%016d", commonPrefix, n))
+	},
+}
+
+// makeKey makes the keys, of the kind picked by -keys; keyLen is how
+// long they are. setKeyKind sets both.
+var makeKey = keyKinds["num"]
+var keyLen = 16
+
+// commonPrefix is what the prefix keys start with.
+var commonPrefix string
+
+// setKeyKind checks -keys and -commonprefix and makes makeKey make
+// keys of that kind.
+func setKeyKind() error {
+	mk, ok := keyKinds[keyKind]
+	if !ok {
+		return fmt.Errorf("bad -keys value %q", keyKind)
+	}
+	if commonPrefixLen < 0 {
+		return fmt.Errorf("bad -commonprefix value %d", commonPrefixLen)
+	}
+	commonPrefix = strings.Repeat("p", commonPrefixLen)
+	makeKey = mk
+	keyLen = len(mk(0))
+	return nil
+}
+
+// newItem is the item for random number n below keySpace, with a key
+// of the kind picked by -keys.
+func newItem(n int64) itemT {
+	return itemT{key: makeKey(n), val: valT(n)}
+}
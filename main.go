@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"flag"
 	"fmt"
-	"math/rand"
 	"os"
 	"sort"
 
@@ -27,6 +26,22 @@ type itemT struct {
 	val valT
 }
 
+// bitemT is itemT with the key as a []byte, for B-trees keyed like
+// uART and the skiplists.
+type bitemT struct {
+	key []byte
+	val valT
+}
+
+// bitem is the bitemT of e, sharing its []byte key.
+func bitem(e entry) bitemT {
+	return bitemT{key: e.bkey, val: e.item.val}
+}
+
+func lessB(a, b bitemT) bool {
+	return bytes.Compare(a.key, b.key) < 0
+}
+
 func int64ToItemT(i int64) itemT {
 	return itemT{
 		key: keyT(fmt.Sprintf("%016d", i)),
@@ -46,9 +61,11 @@ func less(a, b interface{}) bool {
 	return a.(itemT).key < b.(itemT).key
 }
 
-func newUART() *uart.Tree {
+// newUART makes a uART tree. With skipLocking it leaves out its
+// internal locking, as the B-trees do with NoLocks.
+func newUART(skipLocking bool) *uart.Tree {
 	tr := uart.NewArtTree()
-	//tr.SkipLocking = true
+	tr.SkipLocking = skipLocking
 	return tr
 }
 
@@ -80,6 +97,25 @@ func newGBTreeG(degree int) *gbtree.BTreeG[itemT] {
 	return gbtree.NewG(degree, lessG)
 }
 
+func newTBTreeB(degree int) *tbtree.BTreeG[bitemT] {
+	return tbtree.NewBTreeGOptions(lessB, tbtree.Options{
+		NoLocks: true,
+		Degree:  degree,
+	})
+}
+func newGBTreeB(degree int) *gbtree.BTreeG[bitemT] {
+	return gbtree.NewG(degree, lessB)
+}
+
+// The key-only trees. tidwall's Set takes no degree, so it always has
+// the default of 32.
+func newTBTreeS() *tbtree.Set[keyT] {
+	return new(tbtree.Set[keyT])
+}
+func newGBTreeK(degree int) *gbtree.BTreeG[keyT] {
+	return gbtree.NewG(degree, func(a, b keyT) bool { return a < b })
+}
+
 func print_label(label, action string) {
 	fmt.Printf("%-11s %-17s ", label+":", action)
 }
@@ -89,25 +125,113 @@ func main() {
 	degree := 32
 	flag.IntVar(&N, "count", N, "number of items")
 	flag.IntVar(&degree, "degree", degree, "B-tree degree")
+	titrate := false
+	flag.BoolVar(&titrate, "titrate", titrate, "only sweep the degree for get-seq, then exit")
+	flag.StringVar(&traceGlob, "trace", traceGlob, "capture runtime/trace for runs whose action or label:action match this glob")
+	flag.StringVar(&traceDir, "tracedir", traceDir, "directory for the -trace output files")
+	flag.IntVar(&warmupOps, "warmup", warmupOps, "untimed ops to run before timing each run (0 = none)")
+	flag.BoolVar(&shuffleRuns, "shuffle", shuffleRuns, "randomize the order of the implementations within each block")
+	flag.BoolVar(&coldMode, "cold", coldMode, "time each run both cold (caches evicted) and warm (after a warmup pass)")
+	flag.IntVar(&coldBufMB, "coldbuf", coldBufMB, "MB of memory touched to evict the caches for -cold")
+	flag.StringVar(&rangeLengths, "ranges", rangeLengths, "range scan lengths: comma separated, \"all\", or a lo..hi sweep by 10x")
+	flag.StringVar(&prefixLengths, "prefixes", prefixLengths, "prefix lengths for the prefix scan, comma separated")
+	flag.StringVar(&snapWrites, "snapk", snapWrites, "writes between snapshots for the snapshot write runs, comma separated")
+	flag.StringVar(&snapDepths, "snapdepth", snapDepths, "snapshots kept alive for the snapshot write runs, comma separated")
+	flag.StringVar(&publishWrites, "pubk", publishWrites, "writes between published snapshots for the publish runs, comma separated")
+	flag.StringVar(&readerCounts, "readers", readerCounts, "reader goroutines for the publish runs, comma separated")
+	flag.IntVar(&churnRounds, "churn", churnRounds, "how many times N ops each churn scenario runs for")
+	flag.StringVar(&missRates, "missrates", missRates, "percentages of lookups that miss for the miss runs, comma separated")
+	flag.StringVar(&keyKind, "keys", keyKind, "kind of keys: num, path, url or prefix")
+	flag.IntVar(&commonPrefixLen, "commonprefix", commonPrefixLen, "bytes every key shares for -keys prefix")
+	flag.Int64Var(&seed, "seed", seed, "seed for the keys, shuffles and other random draws (0 = pick one)")
+	flag.StringVar(&dumpKeysFile, "dump-keys", dumpKeysFile, "write the keys to this file")
+	flag.StringVar(&loadKeysFile, "load-keys", loadKeysFile, "read the keys from this file, written by -dump-keys, instead of making them; it sets -count, -keys and -commonprefix")
 	flag.Parse()
+	if err := checkTraceGlob(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := setKeyKind(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	lengths, err := parseLengths(rangeLengths)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	plens, err := parseCounts("-prefixes", prefixLengths)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	snapk, err := parseCounts("-snapk", snapWrites)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	snapdepth, err := parseCounts("-snapdepth", snapDepths)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	pubk, err := parseCounts("-pubk", publishWrites)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	readers, err := parseCounts("-readers", readerCounts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	rates, err := parseCounts("-missrates", missRates)
+	if err == nil {
+		for _, r := range rates {
+			if r > 100 {
+				err = fmt.Errorf("bad -missrates value %d", r)
+			}
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 
-	items := make([]itemT, N)
-	itemsM := make(map[int64]bool)
+	var items []itemT
+	if loadKeysFile != "" {
+		var fileSeed int64
+		items, fileSeed, err = readKeys(loadKeysFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		N = len(items)
+		seedSet := false
+		flag.Visit(func(f *flag.Flag) {
+			seedSet = seedSet || f.Name == "seed"
+		})
+		if !seedSet {
+			seed = fileSeed
+		} else if seed != fileSeed {
+			fmt.Fprintf(os.Stderr, "warning: -seed %d is not the seed %d of %s; the shuffles and draws will not match the run that wrote it\n",
+				seed, fileSeed, loadKeysFile)
+		}
+		seedRNG(seed)
+	} else {
+		seedRNG(seed)
+		items = makeItems(N)
+	}
+	if dumpKeysFile != "" {
+		if err := writeKeys(dumpKeysFile, items); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
 	itemsBinaryKey := make([][]byte, N)
 	leafset := make([]*uart.Leaf, N)
-	for i := 0; i < N; i++ {
-		for {
-			key := rand.Int63n(10000000000000000)
-			if !itemsM[key] {
-				itemsM[key] = true
-				items[i] = int64ToItemT(key)
-				if len(items[i].key) != 16 {
-					panic("!")
-				}
-				leafset[i] = &uart.Leaf{}
-				break
-			}
-		}
+	for i := range leafset {
+		leafset[i] = &uart.Leaf{}
 	}
 
 	lotsa.Output = os.Stdout
@@ -124,7 +248,7 @@ func main() {
 
 	shuffleInts := func() {
 		for i := range items {
-			j := rand.Intn(i + 1)
+			j := rng.Intn(i + 1)
 			items[i], items[j] = items[j], items[i]
 			itemsBinaryKey[i], itemsBinaryKey[j] = itemsBinaryKey[j], itemsBinaryKey[i]
 		}
@@ -136,11 +260,38 @@ func main() {
 	ttrG := newTBTreeG(degree)
 	ttrGlocking := newTBTreeG_withLocking(degree)
 	ttrM := newTBTreeM(degree)
-	uART := newUART()
-	skiplist := skl.NewSkiplist(int64(N * skl.MaxNodeSize))
+	uART := newUART(false)
+	uARTnolock := newUART(true)
+	uARTleaf := newUART(false)
+	// Every skiplist gets room for N keys and a tombstone for each, as
+	// the delete blocks need.
+	skiplistArena := badgerArena(N, N)
+	skiplist := skl.NewSkiplist(skiplistArena)
 	skipm := skipmap.NewFunc[[]byte, int](func(a, b []byte) bool {
 		return bytes.Compare(a, b) < 0
 	})
+	// The skiplist's arena is allocated before the timing, so its
+	// bytes/op leave it out; skiplistReport shows how much of it the
+	// block used instead, after checking that get finds every key, or
+	// none of them after a delete.
+	skiplistReport := func(action string, want bool) {
+		for _, k := range itemsBinaryKey {
+			if badgerFound(skiplist.Get(k), k) != want {
+				panic(fmt.Sprintf("badger/skiplist: %v: found %q is %v", action, k, !want))
+			}
+		}
+		printArena("badger/skiplist", action, skiplist.MemSize(), skiplistArena, N)
+	}
+	slc := &sliceImpl{}
+	gmap := &mapImpl{}
+	bsts := []*bstImpl{newRBImpl(), newTreapImpl()}
+	// The in-repo contenders also run the pivot and scan blocks, through
+	// their adapters.
+	baselines := []interface {
+		impl
+		ascender
+		descender
+	}{slc, gmap, bsts[0], bsts[1]}
 
 	withSeq := true
 	withRand := true
@@ -150,40 +301,47 @@ func main() {
 	withScan := true
 	withHints := true
 	withDelete := true
-
-	fmt.Printf("\ndegree=%d, key=string (16 bytes), val=int64, count=%d\n",
-		degree, N)
+	withRange := true
+	withBounded := true
+	withPrefix := true
+	withSnapshot := true
+	withPublish := true
+	withBulk := true
+	withChurn := true
+	withTimeSeries := true
+	withIndex := true
+	withPQ := true
+	withUpsert := true
+	withMiss := true
+	withReverse := true
+	withKeyOnly := true
+	withComparators := true
+	withKeyForms := true
+
+	fmt.Printf("\ndegree=%d, key=string (%d bytes, %s), val=int64, count=%d, seed=%d\n",
+		degree, keyLen, keyKind, N, seed)
 
 	var hint tbtree.PathHint
 	var hintG tbtree.PathHint
 
-	if true {
+	if titrate {
 		// titrate degree
 
 		sortInts()
 		for _, d := range []int{2, 4, 8, 16, 32, 64, 128, 256, 512, 1024, 2048, 3000, 4096, 10_000} {
-			//print_label("tidwall", fmt.Sprintf("get-seq degree %v", d))
-			print_label("google", fmt.Sprintf("get-seq degree %v", d))
-			ttr = newTBTree(d)
-			//ttrGlocking = newTBTreeG_withLocking(d)
-			//ttrGlocking = newTBTreeG(d) // without locks?
-			ttr := newGBTree(d) // google b-tree?
+			gtr := newGBTree(d)
 			for i := range N {
-				//ttr.Set(items[i])
-				ttr.ReplaceOrInsert(items[i])
+				gtr.ReplaceOrInsert(items[i])
 			}
-			lotsa.Ops(N, 1, func(i, _ int) {
-				//re := ttr.Get(items[i])
-				//if re == nil {
-				//	panic(re)
-				//}
-				re := ttr.Get(items[i])
+			benchOps("google", fmt.Sprintf("get-seq degree %v", d), N, 1, func(i, _ int) {
+				re := gtr.Get(items[i])
 				if re == nil {
 					panic(re)
 				}
 			})
 
 		}
+		return
 	}
 	/*
 	   google:     get-seq degree 2  1,000,000 ops in 511ms, 1,956,237/sec, 511 ns/op
@@ -230,14 +388,10 @@ func main() {
 	   tidwall:    get-seq degree 2048 1,000,000 ops in 1512ms, 661,302/sec, 1512 ns/op, 480 bytes, 0.0 bytes/op
 	   tidwall:    get-seq degree 3000 1,000,000 ops in 1526ms, 655,190/sec, 1526 ns/op, 384 bytes, 0.0 bytes/op
 	*/
-	return
 
 	if false {
 		// fill up, titrate degree for seq read
 		for _, d := range []int{2, 4, 8, 16, 32, 64, 128, 256, 512, 1024, 2048, 3000} {
-			//print_label("tidwall(G)", fmt.Sprintf("get-seq degree %v", d))
-			print_label("google(G)", fmt.Sprintf("get-seq degree %v", d))
-
 			//ttrGlocking = newTBTreeG_withLocking(d)
 			//ttrGlocking = newTBTreeG(d) // without locks?
 			ttrGlocking := newGBTree(d) // google b-tree?
@@ -246,7 +400,8 @@ func main() {
 				ttrGlocking.ReplaceOrInsert(items[i])
 			}
 
-			lotsa.Ops(N, 1, func(i, _ int) {
+			//benchOps("tidwall(G)", fmt.Sprintf("get-seq degree %v", d), N, 1, func(i, _ int) {
+			benchOps("google(G)", fmt.Sprintf("get-seq degree %v", d), N, 1, func(i, _ int) {
 				re := ttrGlocking.Get(items[i])
 				if re == nil {
 					panic(re)
@@ -355,223 +510,331 @@ func main() {
 		println("** sequential set **")
 		sortInts()
 
+		b := newBlock(N)
 		// google
-		print_label("google", "set-seq")
-		gtr = newGBTree(degree)
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("google", "set-seq", func() {
+			gtr = newGBTree(degree)
+		}, func(i, _ int) {
 			gtr.ReplaceOrInsert(items[i])
 		})
-
-		print_label("google(G)", "set-seq")
-		gtrG = newGBTreeG(degree)
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("google(G)", "set-seq", func() {
+			gtrG = newGBTreeG(degree)
+		}, func(i, _ int) {
 			gtrG.ReplaceOrInsert(items[i])
 		})
 
 		// non-generics tidwall
-		print_label("tidwall", "set-seq")
-		ttr = newTBTree(degree)
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("tidwall", "set-seq", func() {
+			ttr = newTBTree(degree)
+		}, func(i, _ int) {
 			ttr.Set(items[i])
 		})
-		print_label("tidwall(G)", "set-seq")
-		ttrG = newTBTreeG(degree)
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("tidwall(G)", "set-seq", func() {
+			ttrG = newTBTreeG(degree)
+		}, func(i, _ int) {
 			ttrG.Set(items[i])
 		})
-		print_label("tidwall(M)", "set-seq")
-		ttrM = newTBTreeM(degree)
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("tidwall(M)", "set-seq", func() {
+			ttrM = newTBTreeM(degree)
+		}, func(i, _ int) {
 			ttrM.Set(items[i].key, items[i].val)
 		})
 
-		print_label("tidwall(G) with locking", "set-seq")
-		ttrGlocking = newTBTreeG_withLocking(degree)
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("tidwall(G) with locking", "set-seq", func() {
+			ttrGlocking = newTBTreeG_withLocking(degree)
+		}, func(i, _ int) {
 			ttrGlocking.Set(items[i])
 		})
-		ttrGlocking.Get(items[0]) // prevent GC til after lotsa can report.
 
-		print_label("badger/skiplist", "set-seq")
-		skiplist = skl.NewSkiplist(int64(N * skl.MaxNodeSize))
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("badger/skiplist", "set-seq", func() {
+			skiplist = skl.NewSkiplist(skiplistArena)
+		}, func(i, _ int) {
 			skiplist.Put(itemsBinaryKey[i], y.ValueStruct{Value: itemsBinaryKey[i], Meta: 0, UserMeta: 0})
 		})
-		skiplist.Get(itemsBinaryKey[0]) // try to prevent too soon GC, does not appear to be working.
 
-		print_label("zhangyunhao116/skipmap", "set-seq")
-		skipm = skipmap.NewFunc[[]byte, int](func(a, b []byte) bool {
-			return bytes.Compare(a, b) < 0
-		})
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("zhangyunhao116/skipmap", "set-seq", func() {
+			skipm = skipmap.NewFunc[[]byte, int](func(a, b []byte) bool {
+				return bytes.Compare(a, b) < 0
+			})
+		}, func(i, _ int) {
 			skipm.Store(itemsBinaryKey[i], i)
 		})
-		skipm.Load(itemsBinaryKey[0]) // prevent GC too soon.
-
-		//fmt.Printf("back from lotsa.Ops for tidwall(M)\n")
 
-		print_label("uART", "set-seq")
-		uART = newUART()
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("uART", "set-seq", func() {
+			uART = newUART(false)
+		}, func(i, _ int) {
 			// remember that Insert copies key, and makes a new leaf.
-			if true {
-				// uART uses 3x the memory of btrees.
-				// tidwall(G): set-seq 1,000,000 ops in 261ms, 3,836,715/sec, 260 ns/op, 49.2 MB, 51.6 bytes/op
-				// uART:       set-seq 1,000,000 ops in 330ms, 3,030,409/sec, 329 ns/op, 166.0 MB, 174.0 bytes/op
-
-				uART.Insert(itemsBinaryKey[i], i)
-			} else {
-				// The key copy could be avoided like this, but the NewLeaf is unavoidable.
-				// uART still uses 2x the memory of btrees, not counting the Leaf overhead.
-				// tidwall(G): set-seq 1,000,000 ops in 260ms, 3,844,936/sec, 260 ns/op, 49.2 MB, 51.6 bytes/op
-				// uART:       set-seq 1,000,000 ops in 255ms, 3,928,167/sec, 254 ns/op, 97.3 MB, 102.0 bytes/op
-				lf := leafset[i]
-				lf.Key = itemsBinaryKey[i]
-				lf.Value = i
-				uART.InsertLeaf(lf)
-			}
+			// uART uses 3x the memory of btrees.
+			// tidwall(G): set-seq 1,000,000 ops in 261ms, 3,836,715/sec, 260 ns/op, 49.2 MB, 51.6 bytes/op
+			// uART:       set-seq 1,000,000 ops in 330ms, 3,030,409/sec, 329 ns/op, 166.0 MB, 174.0 bytes/op
+			uART.Insert(itemsBinaryKey[i], i)
+		})
+		b.add("uART(leaf)", "set-seq", func() {
+			uARTleaf = newUART(false)
+		}, func(i, _ int) {
+			// The key copy is avoided like this, but the NewLeaf is unavoidable.
+			// uART still uses 2x the memory of btrees, not counting the Leaf overhead.
+			// tidwall(G): set-seq 1,000,000 ops in 260ms, 3,844,936/sec, 260 ns/op, 49.2 MB, 51.6 bytes/op
+			// uART:       set-seq 1,000,000 ops in 255ms, 3,928,167/sec, 254 ns/op, 97.3 MB, 102.0 bytes/op
+			lf := leafset[i]
+			lf.Key = itemsBinaryKey[i]
+			lf.Value = i
+			uARTleaf.InsertLeaf(lf)
+		})
+		b.add("uART(nolock)", "set-seq", func() {
+			uARTnolock = newUART(true)
+		}, func(i, _ int) {
+			uARTnolock.Insert(itemsBinaryKey[i], i)
 		})
 
-		//_ = uART // does not suffice to keep uART from
-		//  being GC-ed before lotsa can measure its memory consumption!
-		// We do this to prevent that; otherwise mem measurement will be zero.
-		for range uart.Ascend(uART, nil, nil) {
-			break
+		b.add("sorted slice", "set-seq", slc.reset, func(i, _ int) {
+			slc.set(entry{item: items[i]})
+		})
+		b.add("Go map", "set-seq", gmap.reset, func(i, _ int) {
+			gmap.set(entry{item: items[i]})
+		})
+		for _, t := range bsts {
+			b.add(t.name(), "set-seq", t.reset, func(i, _ int) {
+				t.set(entry{item: items[i]})
+			})
 		}
 
 		if withHints {
-			print_label("tidwall", "set-seq-hint")
-			ttr = newTBTree(degree)
-			lotsa.Ops(N, 1, func(i, _ int) {
+			b.add("tidwall", "set-seq-hint", func() {
+				ttr = newTBTree(degree)
+				hint = tbtree.PathHint{}
+			}, func(i, _ int) {
 				ttr.SetHint(items[i], &hint)
 			})
-			print_label("tidwall(G)", "set-seq-hint")
-			ttrG = newTBTreeG(degree)
-			lotsa.Ops(N, 1, func(i, _ int) {
+			b.add("tidwall(G)", "set-seq-hint", func() {
+				ttrG = newTBTreeG(degree)
+				hintG = tbtree.PathHint{}
+			}, func(i, _ int) {
 				ttrG.SetHint(items[i], &hintG)
 			})
 		}
-		print_label("tidwall", "load-seq")
-		ttr = newTBTree(degree)
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("tidwall", "load-seq", func() {
+			ttr = newTBTree(degree)
+		}, func(i, _ int) {
 			ttr.Load(items[i])
 		})
-		print_label("tidwall(G)", "load-seq")
-		ttrG = newTBTreeG(degree)
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("tidwall(G)", "load-seq", func() {
+			ttrG = newTBTreeG(degree)
+		}, func(i, _ int) {
 			ttrG.Load(items[i])
 		})
-		print_label("tidwall(M)", "load-seq")
-		ttrM = newTBTreeM(degree)
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("tidwall(M)", "load-seq", func() {
+			ttrM = newTBTreeM(degree)
+		}, func(i, _ int) {
 			ttrM.Load(items[i].key, items[i].val)
 		})
+		b.exec()
+		skiplistReport("set-seq", true)
 
 		println()
 		println("** sequential get **")
 		sortInts()
 
-		print_label("google", "get-seq")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b = newBlock(N)
+		b.add("google", "get-seq", nil, func(i, _ int) {
 			re := gtr.Get(items[i])
 			if re == nil {
 				panic(re)
 			}
 		})
-		print_label("google(G)", "get-seq")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("google(G)", "get-seq", nil, func(i, _ int) {
 			re, ok := gtrG.Get(items[i])
 			if !ok {
 				panic(re)
 			}
 		})
-		print_label("tidwall", "get-seq")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("tidwall", "get-seq", nil, func(i, _ int) {
 			re := ttr.Get(items[i])
 			if re == nil {
 				panic(re)
 			}
 		})
-		print_label("tidwall(G)", "get-seq")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("tidwall(G)", "get-seq", nil, func(i, _ int) {
 			re, ok := ttrG.Get(items[i])
 			if !ok {
 				panic(re)
 			}
 		})
-		print_label("tidwall(M)", "get-seq")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("tidwall(M)", "get-seq", nil, func(i, _ int) {
 			re, ok := ttrM.Get(items[i].key)
 			if !ok {
 				panic(re)
 			}
 		})
+		b.add("uART", "get-seq", nil, func(i, _ int) {
+			if _, _, ok := uART.FindExact(itemsBinaryKey[i]); !ok {
+				panic(items[i].key)
+			}
+		})
+		b.add("uART(nolock)", "get-seq", nil, func(i, _ int) {
+			if _, _, ok := uARTnolock.FindExact(itemsBinaryKey[i]); !ok {
+				panic(items[i].key)
+			}
+		})
+		b.add("sorted slice", "get-seq", nil, func(i, _ int) {
+			if !slc.get(entry{item: items[i]}) {
+				panic(items[i].key)
+			}
+		})
+		b.add("Go map", "get-seq", nil, func(i, _ int) {
+			if !gmap.get(entry{item: items[i]}) {
+				panic(items[i].key)
+			}
+		})
+		for _, t := range bsts {
+			b.add(t.name(), "get-seq", nil, func(i, _ int) {
+				if !t.get(entry{item: items[i]}) {
+					panic(items[i].key)
+				}
+			})
+		}
 		if withHints {
-			print_label("tidwall", "get-seq-hint")
-			lotsa.Ops(N, 1, func(i, _ int) {
+			b.add("tidwall", "get-seq-hint", nil, func(i, _ int) {
 				re := ttr.GetHint(items[i], &hint)
 				if re == nil {
 					panic(re)
 				}
 			})
-			print_label("tidwall(G)", "get-seq-hint")
-			lotsa.Ops(N, 1, func(i, _ int) {
+			b.add("tidwall(G)", "get-seq-hint", nil, func(i, _ int) {
 				re, ok := ttrG.GetHint(items[i], &hintG)
 				if !ok {
 					panic(re)
 				}
 			})
 		}
+		b.exec()
+	}
+
+	// Delete runs rebuild their structure in setup, so that a warmup
+	// or a second (warm) pass has something left to delete.
+	fillG := func() {
+		ttrG = newTBTreeG(degree)
+		for _, item := range items {
+			ttrG.Set(item)
+		}
+	}
+	fillGlocking := func() {
+		ttrGlocking = newTBTreeG_withLocking(degree)
+		for _, item := range items {
+			ttrGlocking.Set(item)
+		}
+	}
+	fillGoogle := func() {
+		gtr = newGBTree(degree)
+		for _, item := range items {
+			gtr.ReplaceOrInsert(item)
+		}
+	}
+	fillGoogleG := func() {
+		gtrG = newGBTreeG(degree)
+		for _, item := range items {
+			gtrG.ReplaceOrInsert(item)
+		}
+	}
+	fillUART := func() {
+		uART = newUART(false)
+		for i := range itemsBinaryKey {
+			uART.Insert(itemsBinaryKey[i], i)
+		}
+	}
+	fillUARTnolock := func() {
+		uARTnolock = newUART(true)
+		for i := range itemsBinaryKey {
+			uARTnolock.Insert(itemsBinaryKey[i], i)
+		}
+	}
+	fillSkiplist := func() {
+		skiplist = skl.NewSkiplist(skiplistArena)
+		for i := range itemsBinaryKey {
+			skiplist.Put(itemsBinaryKey[i], y.ValueStruct{Value: itemsBinaryKey[i]})
+		}
+	}
+	fillSkipmap := func() {
+		skipm = skipmap.NewFunc[[]byte, int](func(a, b []byte) bool {
+			return bytes.Compare(a, b) < 0
+		})
+		for i := range itemsBinaryKey {
+			skipm.Store(itemsBinaryKey[i], i)
+		}
+	}
+	// A sorted slice insert or delete copies the tail, so it only
+	// gets linearOps of them, on a slice that is all but full.
+	fillSliceBut := func(ops int) func() {
+		return func() {
+			fill(slc, entries(items[:N-ops], itemsBinaryKey[:N-ops]))
+		}
+	}
+	fillMap := func() {
+		fill(gmap, entries(items, itemsBinaryKey))
+		gmap.sorted()
+	}
+	sliceOps := min(N, linearOps)
+	fillBSTs := func() {
+		for _, t := range bsts {
+			fill(t, entries(items, itemsBinaryKey))
+		}
 	}
 
 	if withDelete {
 		println()
 		println("** sequential delete **")
+		sortInts()
 
-		print_label("tidwall(G)", "seq-delete")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b := newBlock(N)
+		b.add("tidwall(G)", "seq-delete", fillG, func(i, _ int) {
 			ttrG.Delete(items[i])
 		})
 
-		print_label("uART", "seq-delete")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("uART", "seq-delete", fillUART, func(i, _ int) {
 			uART.Remove(itemsBinaryKey[i])
 		})
+		b.add("uART(nolock)", "seq-delete", fillUARTnolock, func(i, _ int) {
+			uARTnolock.Remove(itemsBinaryKey[i])
+		})
 
-		print_label("google(G)", "seq-delete")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("google(G)", "seq-delete", fillGoogleG, func(i, _ int) {
 			gtrG.Delete(items[i])
 		})
 
-		print_label("google", "seq-delete")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("google", "seq-delete", fillGoogle, func(i, _ int) {
 			gtr.Delete(items[i])
 		})
 
-		print_label("tidwall(G) with locking", "seq-delete")
-		ttrGlocking = newTBTreeG_withLocking(degree)
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("tidwall(G) with locking", "seq-delete", fillGlocking, func(i, _ int) {
 			ttrGlocking.Delete(items[i])
 		})
-		ttrGlocking.Get(items[0]) // prevent GC til after lotsa can report.
 
-		// does not appear to support delete
-		//print_label("badger/skiplist", "seq-delete")
-		//skiplist = skl.NewSkiplist(int64(N * skl.MaxNodeSize))
-		//lotsa.Ops(N, 1, func(i, _ int) {
-		//	skiplist.Remove(itemsBinaryKey[i])
-		//})
-		//skiplist.Get(itemsBinaryKey[0]) // try to prevent too soon GC, does not appear to be working.
-
-		print_label("zhangyunhao116/skipmap", "seq-delete")
-		skipm = skipmap.NewFunc[[]byte, int](func(a, b []byte) bool {
-			return bytes.Compare(a, b) < 0
+		// badger/skiplist deletes like badger itself, and its adapter's
+		// del: it puts a tombstone, a value with the delete bit set in
+		// its meta. The arena never gets the space back.
+		b.add("badger/skiplist", "seq-delete", fillSkiplist, func(i, _ int) {
+			skiplist.Put(itemsBinaryKey[i], y.ValueStruct{Meta: badgerBitDelete})
 		})
-		lotsa.Ops(N, 1, func(i, _ int) {
+
+		b.add("zhangyunhao116/skipmap", "seq-delete", fillSkipmap, func(i, _ int) {
 			skipm.Delete(itemsBinaryKey[i])
 		})
-		skipm.Load(itemsBinaryKey[0]) // prevent GC too soon.
 
+		b.addCount("sorted slice", "seq-delete", sliceOps, fillSliceBut(0), func(i, _ int) {
+			slc.del(entry{item: items[i]})
+		})
+		b.add("Go map", "seq-delete", fillMap, func(i, _ int) {
+			gmap.del(entry{item: items[i]})
+		})
+		for _, t := range bsts {
+			b.add(t.name(), "seq-delete", func() {
+				fill(t, entries(items, itemsBinaryKey))
+			}, func(i, _ int) {
+				t.del(entry{item: items[i]})
+			})
+		}
+		b.exec()
+		skiplistReport("seq-delete", false)
 	}
 
 	if withRand {
@@ -579,97 +842,135 @@ func main() {
 			println()
 			println("** random set **")
 			shuffleInts()
-			print_label("google", "set-rand")
-			gtr = newGBTree(degree)
-			lotsa.Ops(N, 1, func(i, _ int) {
+
+			b := newBlock(N)
+			b.add("google", "set-rand", func() {
+				gtr = newGBTree(degree)
+			}, func(i, _ int) {
 				gtr.ReplaceOrInsert(items[i])
 			})
-			print_label("google(G)", "set-rand")
-			gtrG = newGBTreeG(degree)
-			lotsa.Ops(N, 1, func(i, _ int) {
+			b.add("google(G)", "set-rand", func() {
+				gtrG = newGBTreeG(degree)
+			}, func(i, _ int) {
 				gtrG.ReplaceOrInsert(items[i])
 			})
-			print_label("tidwall", "set-rand")
-			ttr = newTBTree(degree)
-			lotsa.Ops(N, 1, func(i, _ int) {
+			b.add("tidwall", "set-rand", func() {
+				ttr = newTBTree(degree)
+			}, func(i, _ int) {
 				ttr.Set(items[i])
 			})
-			print_label("tidwall(G)", "set-rand")
-			ttrG = newTBTreeG(degree)
-			lotsa.Ops(N, 1, func(i, _ int) {
+			b.add("tidwall(G)", "set-rand", func() {
+				ttrG = newTBTreeG(degree)
+			}, func(i, _ int) {
 				ttrG.Set(items[i])
 			})
-			print_label("uART", "set-rand")
-			uART = newUART()
-			lotsa.Ops(N, 1, func(i, _ int) {
+			b.add("uART", "set-rand", func() {
+				uART = newUART(false)
+			}, func(i, _ int) {
 				uART.Insert(itemsBinaryKey[i], i)
 			})
-			print_label("tidwall(M)", "set-rand")
-			ttrM = newTBTreeM(degree)
-			lotsa.Ops(N, 1, func(i, _ int) {
+			b.add("uART(leaf)", "set-rand", func() {
+				uARTleaf = newUART(false)
+			}, func(i, _ int) {
+				lf := leafset[i]
+				lf.Key = itemsBinaryKey[i]
+				lf.Value = i
+				uARTleaf.InsertLeaf(lf)
+			})
+			b.add("uART(nolock)", "set-rand", func() {
+				uARTnolock = newUART(true)
+			}, func(i, _ int) {
+				uARTnolock.Insert(itemsBinaryKey[i], i)
+			})
+			b.add("tidwall(M)", "set-rand", func() {
+				ttrM = newTBTreeM(degree)
+			}, func(i, _ int) {
 				ttrM.Set(items[i].key, items[i].val)
 			})
 
-			print_label("tidwall(G) with locking", "set-rand")
-			ttrGlocking = newTBTreeG_withLocking(degree)
-			lotsa.Ops(N, 1, func(i, _ int) {
+			b.add("tidwall(G) with locking", "set-rand", func() {
+				ttrGlocking = newTBTreeG_withLocking(degree)
+			}, func(i, _ int) {
 				ttrGlocking.Set(items[i])
 			})
-			ttrGlocking.Get(items[0]) // prevent GC til after lotsa can report.
 
-			print_label("badger/skiplist", "set-rand")
-			skiplist = skl.NewSkiplist(int64(N * skl.MaxNodeSize))
-			lotsa.Ops(N, 1, func(i, _ int) {
+			b.add("badger/skiplist", "set-rand", func() {
+				skiplist = skl.NewSkiplist(skiplistArena)
+			}, func(i, _ int) {
 				skiplist.Put(itemsBinaryKey[i], y.ValueStruct{Value: itemsBinaryKey[i], Meta: 0, UserMeta: 0})
 			})
-			skiplist.Get(itemsBinaryKey[0]) // try to prevent too soon GC, does not appear to be working.
 
-			print_label("zhangyunhao116/skipmap", "set-rand")
-			skipm = skipmap.NewFunc[[]byte, int](func(a, b []byte) bool {
-				return bytes.Compare(a, b) < 0
-			})
-			lotsa.Ops(N, 1, func(i, _ int) {
+			b.add("zhangyunhao116/skipmap", "set-rand", func() {
+				skipm = skipmap.NewFunc[[]byte, int](func(a, b []byte) bool {
+					return bytes.Compare(a, b) < 0
+				})
+			}, func(i, _ int) {
 				skipm.Store(itemsBinaryKey[i], i)
 			})
-			skipm.Load(itemsBinaryKey[0]) // prevent GC too soon.
+
+			b.addCount("sorted slice", "set-rand", sliceOps, fillSliceBut(sliceOps), func(i, _ int) {
+				slc.set(entry{item: items[N-sliceOps+i]})
+			})
+			b.add("Go map", "set-rand", gmap.reset, func(i, _ int) {
+				gmap.set(entry{item: items[i]})
+			})
+			for _, t := range bsts {
+				b.add(t.name(), "set-rand", t.reset, func(i, _ int) {
+					t.set(entry{item: items[i]})
+				})
+			}
 
 			if withHints {
-				print_label("tidwall", "set-rand-hint")
-				ttr = newTBTree(degree)
-				lotsa.Ops(N, 1, func(i, _ int) {
+				b.add("tidwall", "set-rand-hint", func() {
+					ttr = newTBTree(degree)
+					hint = tbtree.PathHint{}
+				}, func(i, _ int) {
 					ttr.SetHint(items[i], &hint)
 				})
-				print_label("tidwall(G)", "set-rand-hint")
-				ttrG = newTBTreeG(degree)
-				lotsa.Ops(N, 1, func(i, _ int) {
+				b.add("tidwall(G)", "set-rand-hint", func() {
+					ttrG = newTBTreeG(degree)
+					hintG = tbtree.PathHint{}
+				}, func(i, _ int) {
 					ttrG.SetHint(items[i], &hintG)
 				})
 			}
-			print_label("tidwall", "set-after-copy")
-			ttr2 := ttr.Copy()
-			lotsa.Ops(N, 1, func(i, _ int) {
+			var ttr2 *tbtree.BTree
+			b.add("tidwall", "set-after-copy", func() {
+				ttr = newTBTree(degree)
+				for _, item := range items {
+					ttr.Set(item)
+				}
+				ttr2 = ttr.Copy()
+			}, func(i, _ int) {
 				ttr2.Set(items[i])
 			})
-			print_label("tidwall(G)", "set-after-copy")
-			ttrG2 := ttrG.Copy()
-			lotsa.Ops(N, 1, func(i, _ int) {
+			var ttrG2 *tbtree.BTreeG[itemT]
+			b.add("tidwall(G)", "set-after-copy", func() {
+				ttrG = newTBTreeG(degree)
+				for _, item := range items {
+					ttrG.Set(item)
+				}
+				ttrG2 = ttrG.Copy()
+			}, func(i, _ int) {
 				ttrG2.Set(items[i])
 			})
-			print_label("tidwall", "load-rand")
-			ttr = newTBTree(degree)
-			lotsa.Ops(N, 1, func(i, _ int) {
+			b.add("tidwall", "load-rand", func() {
+				ttr = newTBTree(degree)
+			}, func(i, _ int) {
 				ttr.Load(items[i])
 			})
-			print_label("tidwall(G)", "load-rand")
-			ttrG = newTBTreeG(degree)
-			lotsa.Ops(N, 1, func(i, _ int) {
+			b.add("tidwall(G)", "load-rand", func() {
+				ttrG = newTBTreeG(degree)
+			}, func(i, _ int) {
 				ttrG.Load(items[i])
 			})
-			ttrM = newTBTreeM(degree)
-			print_label("tidwall(M)", "load-rand")
-			lotsa.Ops(N, 1, func(i, _ int) {
+			b.add("tidwall(M)", "load-rand", func() {
+				ttrM = newTBTreeM(degree)
+			}, func(i, _ int) {
 				ttrM.Load(items[i].key, items[i].val)
 			})
+			b.exec()
+			skiplistReport("set-rand", true)
 		}
 
 		if withRandDel {
@@ -677,20 +978,41 @@ func main() {
 			println("** random delete **")
 			shuffleInts()
 
-			print_label("tidwall(G)", "rand-delete")
-			lotsa.Ops(N, 1, func(i, _ int) {
+			b := newBlock(N)
+			b.add("tidwall(G)", "rand-delete", fillG, func(i, _ int) {
 				ttrG.Delete(items[i])
 			})
 
-			print_label("uART", "rand-delete")
-			lotsa.Ops(N, 1, func(i, _ int) {
+			b.add("uART", "rand-delete", fillUART, func(i, _ int) {
 				uART.Remove(itemsBinaryKey[i])
 			})
+			b.add("uART(nolock)", "rand-delete", fillUARTnolock, func(i, _ int) {
+				uARTnolock.Remove(itemsBinaryKey[i])
+			})
 
-			print_label("google(G)", "rand-delete")
-			lotsa.Ops(N, 1, func(i, _ int) {
+			b.add("google(G)", "rand-delete", fillGoogleG, func(i, _ int) {
 				gtrG.Delete(items[i])
 			})
+
+			b.add("badger/skiplist", "rand-delete", fillSkiplist, func(i, _ int) {
+				skiplist.Put(itemsBinaryKey[i], y.ValueStruct{Meta: badgerBitDelete})
+			})
+
+			b.addCount("sorted slice", "rand-delete", sliceOps, fillSliceBut(0), func(i, _ int) {
+				slc.del(entry{item: items[i]})
+			})
+			b.add("Go map", "rand-delete", fillMap, func(i, _ int) {
+				gmap.del(entry{item: items[i]})
+			})
+			for _, t := range bsts {
+				b.add(t.name(), "rand-delete", func() {
+					fill(t, entries(items, itemsBinaryKey))
+				}, func(i, _ int) {
+					t.del(entry{item: items[i]})
+				})
+			}
+			b.exec()
+			skiplistReport("rand-delete", false)
 		}
 
 		println()
@@ -709,59 +1031,86 @@ func main() {
 			ttr.Set(item)
 			ttrM.Set(item.key, item.val)
 		}
+		fillUART()
+		fillUARTnolock()
+		fillSliceBut(0)()
+		fillMap()
+		fillBSTs()
 		shuffleInts()
 
-		print_label("google", "get-rand")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b := newBlock(N)
+		b.add("google", "get-rand", nil, func(i, _ int) {
 			re := gtr.Get(items[i])
 			if re == nil {
 				panic(re)
 			}
 		})
-		print_label("google(G)", "get-rand")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("google(G)", "get-rand", nil, func(i, _ int) {
 			re, ok := gtrG.Get(items[i])
 			if !ok {
 				panic(re)
 			}
 		})
-		print_label("tidwall", "get-rand")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("tidwall", "get-rand", nil, func(i, _ int) {
 			re := ttr.Get(items[i])
 			if re == nil {
 				panic(re)
 			}
 		})
-		print_label("tidwall(G)", "get-rand")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("tidwall(G)", "get-rand", nil, func(i, _ int) {
 			re, ok := ttrG.Get(items[i])
 			if !ok {
 				panic(re)
 			}
 		})
-		print_label("tidwall(M)", "get-rand")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("tidwall(M)", "get-rand", nil, func(i, _ int) {
 			re, ok := ttrM.Get(items[i].key)
 			if !ok {
 				panic(re)
 			}
 		})
+		b.add("uART", "get-rand", nil, func(i, _ int) {
+			if _, _, ok := uART.FindExact(itemsBinaryKey[i]); !ok {
+				panic(items[i].key)
+			}
+		})
+		b.add("uART(nolock)", "get-rand", nil, func(i, _ int) {
+			if _, _, ok := uARTnolock.FindExact(itemsBinaryKey[i]); !ok {
+				panic(items[i].key)
+			}
+		})
+		b.add("sorted slice", "get-rand", nil, func(i, _ int) {
+			if !slc.get(entry{item: items[i]}) {
+				panic(items[i].key)
+			}
+		})
+		b.add("Go map", "get-rand", nil, func(i, _ int) {
+			if !gmap.get(entry{item: items[i]}) {
+				panic(items[i].key)
+			}
+		})
+		for _, t := range bsts {
+			b.add(t.name(), "get-rand", nil, func(i, _ int) {
+				if !t.get(entry{item: items[i]}) {
+					panic(items[i].key)
+				}
+			})
+		}
 		if withHints {
-			print_label("tidwall", "get-rand-hint")
-			lotsa.Ops(N, 1, func(i, _ int) {
+			b.add("tidwall", "get-rand-hint", nil, func(i, _ int) {
 				re := ttr.GetHint(items[i], &hint)
 				if re == nil {
 					panic(re)
 				}
 			})
-			print_label("tidwall(G)", "get-rand-hint")
-			lotsa.Ops(N, 1, func(i, _ int) {
+			b.add("tidwall(G)", "get-rand-hint", nil, func(i, _ int) {
 				re, ok := ttrG.GetHint(items[i], &hintG)
 				if !ok {
 					panic(re)
 				}
 			})
 		}
+		b.exec()
 	}
 
 	if !withRand {
@@ -778,6 +1127,9 @@ func main() {
 			ttr.Set(item)
 			ttrM.Set(item.key, item.val)
 		}
+		fillSliceBut(0)()
+		fillMap()
+		fillBSTs()
 	}
 
 	if withPivot {
@@ -787,65 +1139,58 @@ func main() {
 		println()
 		fmt.Printf("** sequential pivot **\n")
 		fmt.Printf("Test getting %d consecutive items starting at a pivot.\n", M)
-		print_label("google", "ascend-seq")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b := newBlock(N)
+		b.add("google", "ascend-seq", nil, func(i, _ int) {
 			var count int
 			gtr.AscendGreaterOrEqual(items[i], func(item gbtree.Item) bool {
 				count++
 				return count < M
 			})
 		})
-		print_label("google", "descend-seq")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("google", "descend-seq", nil, func(i, _ int) {
 			var count int
 			gtr.DescendLessOrEqual(items[i], func(item gbtree.Item) bool {
 				count++
 				return count < M
 			})
 		})
-		print_label("google(G)", "ascend-seq")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("google(G)", "ascend-seq", nil, func(i, _ int) {
 			var count int
 			gtrG.AscendGreaterOrEqual(items[i], func(item itemT) bool {
 				count++
 				return count < M
 			})
 		})
-		print_label("google(G)", "descend-seq")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("google(G)", "descend-seq", nil, func(i, _ int) {
 			var count int
 			gtrG.DescendLessOrEqual(items[i], func(item itemT) bool {
 				count++
 				return count < M
 			})
 		})
-		print_label("tidwall", "ascend-seq")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("tidwall", "ascend-seq", nil, func(i, _ int) {
 			var count int
 			ttr.Ascend(items[i], func(item any) bool {
 				count++
 				return count < M
 			})
 		})
-		print_label("tidwall", "descend-seq")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("tidwall", "descend-seq", nil, func(i, _ int) {
 			var count int
-			ttr.Ascend(items[i], func(item any) bool {
+			ttr.Descend(items[i], func(item any) bool {
 				count++
 				return count < M
 			})
 		})
 		if withHints {
-			print_label("tidwall", "ascend-seq-hint")
-			lotsa.Ops(N, 1, func(i, _ int) {
+			b.add("tidwall", "ascend-seq-hint", nil, func(i, _ int) {
 				var count int
 				ttr.AscendHint(items[i], func(item any) bool {
 					count++
 					return count < M
 				}, &hint)
 			})
-			print_label("tidwall", "descend-seq-hint")
-			lotsa.Ops(N, 1, func(i, _ int) {
+			b.add("tidwall", "descend-seq-hint", nil, func(i, _ int) {
 				var count int
 				ttr.DescendHint(items[i], func(item any) bool {
 					count++
@@ -853,16 +1198,14 @@ func main() {
 				}, &hint)
 			})
 		}
-		print_label("tidwall(G)", "ascend-seq")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("tidwall(G)", "ascend-seq", nil, func(i, _ int) {
 			var count int
 			ttrG.Ascend(items[i], func(item itemT) bool {
 				count++
 				return count < M
 			})
 		})
-		print_label("tidwall(G)", "descend-seq")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("tidwall(G)", "descend-seq", nil, func(i, _ int) {
 			var count int
 			ttrG.Descend(items[i], func(item itemT) bool {
 				count++
@@ -870,16 +1213,14 @@ func main() {
 			})
 		})
 		if withHints {
-			print_label("tidwall(G)", "ascend-seq-hint")
-			lotsa.Ops(N, 1, func(i, _ int) {
+			b.add("tidwall(G)", "ascend-seq-hint", nil, func(i, _ int) {
 				var count int
 				ttrG.AscendHint(items[i], func(item itemT) bool {
 					count++
 					return count < M
 				}, &hint)
 			})
-			print_label("tidwall(G)", "descend-seq-hint")
-			lotsa.Ops(N, 1, func(i, _ int) {
+			b.add("tidwall(G)", "descend-seq-hint", nil, func(i, _ int) {
 				var count int
 				ttrG.DescendHint(items[i], func(item itemT) bool {
 					count++
@@ -887,8 +1228,23 @@ func main() {
 				}, &hint)
 			})
 		}
-		print_label("tidwall(G)", "iter-seq")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		for _, im := range baselines {
+			b.add(im.name(), "ascend-seq", nil, func(i, _ int) {
+				var count int
+				im.ascend(entry{item: items[i]}, func() bool {
+					count++
+					return count < M
+				})
+			})
+			b.add(im.name(), "descend-seq", nil, func(i, _ int) {
+				var count int
+				im.descend(entry{item: items[i]}, func() bool {
+					count++
+					return count < M
+				})
+			})
+		}
+		b.add("tidwall(G)", "iter-seq", nil, func(i, _ int) {
 			iter := ttrG.Iter()
 			var count int
 			for ok := iter.Seek(items[i]); ok; ok = iter.Next() {
@@ -899,8 +1255,7 @@ func main() {
 			}
 			iter.Release()
 		})
-		print_label("tidwall(G)", "iter-seq-hint")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("tidwall(G)", "iter-seq-hint", nil, func(i, _ int) {
 			iter := ttrG.Iter()
 			var count int
 			for ok := iter.SeekHint(items[i], &hint); ok; ok = iter.Next() {
@@ -911,6 +1266,7 @@ func main() {
 			}
 			iter.Release()
 		})
+		b.exec()
 	}
 
 	if withPivot {
@@ -920,65 +1276,58 @@ func main() {
 		println()
 		fmt.Printf("** random pivot **\n")
 		fmt.Printf("Test getting %d consecutive items starting at a pivot.\n", M)
-		print_label("google", "ascend-rand")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b := newBlock(N)
+		b.add("google", "ascend-rand", nil, func(i, _ int) {
 			var count int
 			gtr.AscendGreaterOrEqual(items[i], func(item gbtree.Item) bool {
 				count++
 				return count < M
 			})
 		})
-		print_label("google", "descend-rand")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("google", "descend-rand", nil, func(i, _ int) {
 			var count int
 			gtr.DescendLessOrEqual(items[i], func(item gbtree.Item) bool {
 				count++
 				return count < M
 			})
 		})
-		print_label("google(G)", "ascend-rand")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("google(G)", "ascend-rand", nil, func(i, _ int) {
 			var count int
 			gtrG.AscendGreaterOrEqual(items[i], func(item itemT) bool {
 				count++
 				return count < M
 			})
 		})
-		print_label("google(G)", "descend-rand")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("google(G)", "descend-rand", nil, func(i, _ int) {
 			var count int
 			gtrG.DescendLessOrEqual(items[i], func(item itemT) bool {
 				count++
 				return count < M
 			})
 		})
-		print_label("tidwall", "ascend-rand")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("tidwall", "ascend-rand", nil, func(i, _ int) {
 			var count int
 			ttr.Ascend(items[i], func(item any) bool {
 				count++
 				return count < M
 			})
 		})
-		print_label("tidwall", "descend-rand")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("tidwall", "descend-rand", nil, func(i, _ int) {
 			var count int
-			ttr.Ascend(items[i], func(item any) bool {
+			ttr.Descend(items[i], func(item any) bool {
 				count++
 				return count < M
 			})
 		})
 		if withHints {
-			print_label("tidwall", "ascend-rand-hint")
-			lotsa.Ops(N, 1, func(i, _ int) {
+			b.add("tidwall", "ascend-rand-hint", nil, func(i, _ int) {
 				var count int
 				ttr.AscendHint(items[i], func(item any) bool {
 					count++
 					return count < M
 				}, &hint)
 			})
-			print_label("tidwall", "descend-rand-hint")
-			lotsa.Ops(N, 1, func(i, _ int) {
+			b.add("tidwall", "descend-rand-hint", nil, func(i, _ int) {
 				var count int
 				ttr.DescendHint(items[i], func(item any) bool {
 					count++
@@ -986,16 +1335,14 @@ func main() {
 				}, &hint)
 			})
 		}
-		print_label("tidwall(G)", "ascend-rand")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("tidwall(G)", "ascend-rand", nil, func(i, _ int) {
 			var count int
 			ttrG.Ascend(items[i], func(item itemT) bool {
 				count++
 				return count < M
 			})
 		})
-		print_label("tidwall(G)", "descend-rand")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("tidwall(G)", "descend-rand", nil, func(i, _ int) {
 			var count int
 			ttrG.Descend(items[i], func(item itemT) bool {
 				count++
@@ -1003,16 +1350,14 @@ func main() {
 			})
 		})
 		if withHints {
-			print_label("tidwall(G)", "ascend-rand-hint")
-			lotsa.Ops(N, 1, func(i, _ int) {
+			b.add("tidwall(G)", "ascend-rand-hint", nil, func(i, _ int) {
 				var count int
 				ttrG.AscendHint(items[i], func(item itemT) bool {
 					count++
 					return count < M
 				}, &hint)
 			})
-			print_label("tidwall(G)", "descend-rand-hint")
-			lotsa.Ops(N, 1, func(i, _ int) {
+			b.add("tidwall(G)", "descend-rand-hint", nil, func(i, _ int) {
 				var count int
 				ttrG.DescendHint(items[i], func(item itemT) bool {
 					count++
@@ -1020,8 +1365,23 @@ func main() {
 				}, &hint)
 			})
 		}
-		print_label("tidwall(G)", "iter-rand")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		for _, im := range baselines {
+			b.add(im.name(), "ascend-rand", nil, func(i, _ int) {
+				var count int
+				im.ascend(entry{item: items[i]}, func() bool {
+					count++
+					return count < M
+				})
+			})
+			b.add(im.name(), "descend-rand", nil, func(i, _ int) {
+				var count int
+				im.descend(entry{item: items[i]}, func() bool {
+					count++
+					return count < M
+				})
+			})
+		}
+		b.add("tidwall(G)", "iter-rand", nil, func(i, _ int) {
 			iter := ttrG.Iter()
 			var count int
 			for ok := iter.Seek(items[i]); ok; ok = iter.Next() {
@@ -1032,8 +1392,7 @@ func main() {
 			}
 			iter.Release()
 		})
-		print_label("tidwall(G)", "iter-rand-hint")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("tidwall(G)", "iter-rand-hint", nil, func(i, _ int) {
 			iter := ttrG.Iter()
 			var count int
 			for ok := iter.SeekHint(items[i], &hint); ok; ok = iter.Next() {
@@ -1044,46 +1403,43 @@ func main() {
 			}
 			iter.Release()
 		})
+		b.exec()
 	}
 
 	if withScan {
 		println()
 		println("** scan **")
 		println("Test scanning over every item in the tree")
-		print_label("google", "ascend")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b := newBlock(N)
+		b.add("google", "ascend", nil, func(i, _ int) {
 			if i == 0 {
 				gtr.Ascend(func(item gbtree.Item) bool {
 					return true
 				})
 			}
 		})
-		print_label("google(G)", "ascend")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("google(G)", "ascend", nil, func(i, _ int) {
 			if i == 0 {
 				gtrG.Ascend(func(item itemT) bool {
 					return true
 				})
 			}
 		})
-		print_label("tidwall", "ascend")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("tidwall", "ascend", nil, func(i, _ int) {
 			if i == 0 {
 				ttr.Ascend(nil, func(item interface{}) bool {
 					return true
 				})
 			}
 		})
-		print_label("tidwall(G)", "scan")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("tidwall(G)", "scan", nil, func(i, _ int) {
 			if i == 0 {
 				ttrG.Scan(func(item itemT) bool {
 					return true
 				})
 			}
 		})
-		print_label("tidwall(G)", "walk")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("tidwall(G)", "walk", nil, func(i, _ int) {
 			if i == 0 {
 				ttrG.Walk(func(items []itemT) bool {
 					for j := 0; j < len(items); j++ {
@@ -1093,8 +1449,7 @@ func main() {
 				})
 			}
 		})
-		print_label("tidwall(G)", "iter")
-		lotsa.Ops(N, 1, func(i, _ int) {
+		b.add("tidwall(G)", "iter", nil, func(i, _ int) {
 			if i == 0 {
 				iter := ttrG.Iter()
 				for ok := iter.First(); ok; ok = iter.Next() {
@@ -1102,6 +1457,113 @@ func main() {
 				iter.Release()
 			}
 		})
+		for _, im := range baselines {
+			b.add(im.name(), "ascend", nil, func(i, _ int) {
+				if i == 0 {
+					im.ascend(entry{}, func() bool {
+						return true
+					})
+				}
+			})
+		}
+		b.exec()
+	}
+
+	if withRange {
+		sortInts()
+		shuffleInts()
+		benchRangeScans(newImpls(degree, N), entries(items, itemsBinaryKey), lengths)
+	}
+
+	if withBounded {
+		sortInts()
+		shuffleInts()
+		benchBoundedRanges(newImpls(degree, N), entries(items, itemsBinaryKey), lengths)
+	}
+
+	if withPrefix {
+		sortInts()
+		shuffleInts()
+		benchPrefixScans(newImpls(degree, N), entries(items, itemsBinaryKey), plens)
+	}
+
+	if withSnapshot {
+		sortInts()
+		shuffleInts()
+		benchSnapshots(newImpls(degree, N), entries(items, itemsBinaryKey), snapk, snapdepth)
+	}
+
+	if withPublish {
+		sortInts()
+		shuffleInts()
+		benchPublish(newImpls(degree, N), entries(items, itemsBinaryKey), pubk, readers)
+	}
+
+	if withBulk {
+		sortInts()
+		benchBulkLoad(newImpls(degree, N), entries(items, itemsBinaryKey))
+	}
+
+	if withChurn && churnRounds > 0 {
+		sortInts()
+		shuffleInts()
+		benchChurn(newImpls(degree, N), entries(items, itemsBinaryKey), churnRounds)
+	}
+
+	if withTimeSeries {
+		benchTimeSeries(newImpls(degree, N), N)
+	}
+
+	if withIndex {
+		sortInts()
+		shuffleInts()
+		benchIndex(newImpls(degree, N), entries(items, itemsBinaryKey))
+	}
+
+	if withPQ {
+		sortInts()
+		shuffleInts()
+		benchPriorityQueue(newImpls(degree, N), entries(items, itemsBinaryKey))
+	}
+
+	if withUpsert {
+		sortInts()
+		shuffleInts()
+		benchUpsert(newImpls(degree, N), entries(items, itemsBinaryKey))
+	}
+
+	if withMiss {
+		sortInts()
+		shuffleInts()
+		benchMisses(newImpls(degree, N), entries(items, itemsBinaryKey), rates)
+	}
+
+	if withReverse {
+		sortInts()
+		shuffleInts()
+		benchReverse(newImpls(degree, N), entries(items, itemsBinaryKey), lengths)
+	}
+
+	if withKeyOnly {
+		sortInts()
+		shuffleInts()
+		benchKeyOnly(newImpls(degree, N), entries(items, itemsBinaryKey))
+	}
+
+	if withComparators {
+		sortInts()
+		shuffleInts()
+		benchComparators(newImpls(degree, N), entries(items, itemsBinaryKey))
+	}
+
+	if withKeyForms {
+		sortInts()
+		shuffleInts()
+		benchKeyForms(newImpls(degree, N), entries(items, itemsBinaryKey))
+	}
+
+	if coldMode {
+		printColdWarm()
 	}
 }
 
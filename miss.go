@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+)
+
+// -missrates lists the percentages of lookups that miss, for the miss
+// block.
+var missRates = "50,100"
+
+// benchMisses times lookups of keys that are not there, mixed with
+// ones that are, at each miss rate. The missing keys come in three
+// kinds:
+//
+//   - between: random keys, which fall between existing ones.
+//   - beyond: keys past the largest one.
+//   - prefix: an existing key with its last digit changed, so it
+//     shares all but one byte with a key that is there.
+//
+// Every lookup is checked: a hit must be found and a miss must not.
+//
+// ents must already be in random order.
+func benchMisses(impls []impl, ents []entry, rates []int) {
+	println()
+	println("** miss **")
+	println("Test looking up keys that are not there, mixed with ones that are.")
+	for _, im := range impls {
+		fill(im, ents)
+	}
+
+	n := len(ents)
+	have := make(map[keyT]bool, n)
+	for _, e := range ents {
+		have[e.item.key] = true
+	}
+	misses := map[string][]entry{
+		"between": freshEntries(ents, n),
+		"beyond":  make([]entry, n),
+		"prefix":  make([]entry, n),
+	}
+	for i, e := range ents {
+		// '~' sorts after every byte of every kind of key, so these are
+		// past every key.
+		key := "~" + string(e.item.key[1:])
+		misses["beyond"][i] = entry{item: itemT{key: keyT(key)}, bkey: []byte(key)}
+
+		b := []byte(e.item.key)
+		last := len(b) - 1
+		for d := 1; d <= 10; d++ {
+			b[last] = '0' + (e.item.key[last]-'0'+byte(d))%10
+			if d == 10 {
+				b[last] = ':'
+			}
+			if !have[keyT(b)] {
+				break
+			}
+		}
+		misses["prefix"][i] = entry{item: itemT{key: keyT(b)}, bkey: b}
+	}
+
+	for _, kind := range []string{"between", "beyond", "prefix"} {
+		for _, rate := range rates {
+			queries := make([]entry, n)
+			want := make([]bool, n)
+			for i := range queries {
+				if rng.Intn(100) < rate {
+					queries[i] = misses[kind][i]
+				} else {
+					queries[i], want[i] = ents[i], true
+				}
+			}
+			action := fmt.Sprintf("miss-%s-%d", kind, rate)
+			b := newBlock(n)
+			for _, im := range impls {
+				b.add(im.name(), action, nil, func(i, _ int) {
+					if im.get(queries[i]) != want[i] {
+						panic(fmt.Sprintf("%v: get %q = %v, want %v",
+							im.name(), queries[i].bkey, !want[i], want[i]))
+					}
+				})
+			}
+			b.exec()
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"runtime/trace"
+	"sync"
+	"time"
+
+	"github.com/tidwall/lotsa"
+)
+
+// result is what one benchOps run measured.
+type result struct {
+	count   int
+	threads int
+	elapsed time.Duration
+	alloc   uint64
+}
+
+func (r result) nsop() float64 {
+	if r.count == 0 {
+		return 0
+	}
+	return float64(r.elapsed.Nanoseconds()) / float64(r.count)
+}
+
+// benchOps prints the label and then does what lotsa.Ops does: runs op
+// count times spread over threads goroutines and writes lotsa's usual
+// output line. We carry our own copy of the loop so that runs can be
+// hooked, e.g. captured with runtime/trace when -trace selects them.
+//
+// Wrapping lotsa.Ops would not do: it takes no hooks and returns
+// nothing, so a trace started around it would also cover its GC and
+// ReadMemStats calls, and there would be no way to open a trace region
+// in each of its goroutines around just the ops.
+func benchOps(label, action string, count, threads int, op func(i, thread int)) (res result) {
+	print_label(label, action)
+	beforeTiming()
+
+	ctx := context.Background()
+	traced := traceMatch(label, action)
+	if traced {
+		stop := startTrace(label, action)
+		defer stop()
+		var task *trace.Task
+		ctx, task = trace.NewTask(ctx, label)
+		defer task.End()
+	}
+
+	var start time.Time
+	var wg sync.WaitGroup
+	wg.Add(threads)
+	var ms1 runtime.MemStats
+	output := lotsa.Output
+	if output != nil {
+		if lotsa.MemUsage {
+			runtime.GC()
+			runtime.ReadMemStats(&ms1)
+		}
+		start = time.Now()
+	}
+	for i := 0; i < threads; i++ {
+		s, e := count/threads*i, count/threads*(i+1)
+		if i == threads-1 {
+			e = count
+		}
+		go func(i, s, e int) {
+			defer wg.Done()
+			if traced {
+				defer trace.StartRegion(ctx, action).End()
+			}
+			for j := s; j < e; j++ {
+				op(j, i)
+			}
+		}(i, s, e)
+	}
+	wg.Wait()
+
+	res = result{count: count, threads: threads}
+	if output != nil {
+		dur := time.Since(start)
+		var alloc uint64
+		if lotsa.MemUsage {
+			runtime.GC()
+			var ms2 runtime.MemStats
+			runtime.ReadMemStats(&ms2)
+			if ms1.HeapAlloc < ms2.HeapAlloc {
+				alloc = ms2.HeapAlloc - ms1.HeapAlloc
+			}
+		}
+		lotsa.WriteOutput(output, count, threads, dur, alloc)
+		res.elapsed, res.alloc = dur, alloc
+	}
+	return res
+}
+
+// heapAlloc collects garbage and returns the bytes still in use, for
+// measuring what a structure retains outside of a timed run.
+func heapAlloc() uint64 {
+	var ms runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&ms)
+	return ms.HeapAlloc
+}
+
+// totalAlloc returns the bytes allocated so far, garbage or not.
+func totalAlloc() uint64 {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	return ms.TotalAlloc
+}
+
+// memString formats bytes the way lotsa does.
+func memString(alloc uint64) string {
+	switch {
+	case alloc <= 1024:
+		return fmt.Sprintf("%d bytes", alloc)
+	case alloc <= 1024*1024:
+		return fmt.Sprintf("%.1f KB", float64(alloc)/1024)
+	case alloc <= 1024*1024*1024:
+		return fmt.Sprintf("%.1f MB", float64(alloc)/1024/1024)
+	default:
+		return fmt.Sprintf("%.1f GB", float64(alloc)/1024/1024/1024)
+	}
+}
+
+// printArena reports how much of a pre-sized arena holding keys keys
+// is in use, and what that comes to per key, to set beside the
+// bytes/op of the structures that allocate as they go.
+func printArena(label, action string, used, size int64, keys int) {
+	print_label(label, action)
+	fmt.Printf("arena %s used of %s (%.1f%%), %.1f bytes/key\n",
+		memString(uint64(used)), memString(uint64(size)),
+		100*float64(used)/float64(size), float64(used)/float64(max(keys, 1)))
+}
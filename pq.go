@@ -0,0 +1,143 @@
+package main
+
+import (
+	"container/heap"
+	"fmt"
+	"sort"
+)
+
+// itemHeap is container/heap over the B-tree items, as the unordered
+// baseline for the priority queue block.
+type itemHeap []itemT
+
+func (h itemHeap) Len() int           { return len(h) }
+func (h itemHeap) Less(i, j int) bool { return lessG(h[i], h[j]) }
+func (h itemHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *itemHeap) Push(x any)        { *h = append(*h, x.(itemT)) }
+func (h *itemHeap) Pop() any {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
+
+// benchPriorityQueue uses the ordered structures as priority queues:
+//
+//   - min, max: peek at the smallest or largest key of a full one.
+//   - popmin, popmax: pop until empty.
+//   - push-pop: hold N/2 keys, then push one and pop the smallest,
+//     N/2 times, like a heap.
+//
+// container/heap does min, popmin and push-pop, for comparison.
+//
+// ents must already be in random order.
+func benchPriorityQueue(impls []impl, ents []entry) {
+	println()
+	println("** priority queue **")
+	println("Test peeking at and popping the smallest and largest keys.")
+
+	n := len(ents)
+	sorted := append([]entry(nil), ents...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].item.key < sorted[j].item.key
+	})
+
+	// push-pop starts with ents[:half] and pushes ents[half:]; popped
+	// is what comes out, worked out here with container/heap.
+	half := n / 2
+	pushes := ents[half:]
+	popped := make([]entry, len(pushes))
+	{
+		var h itemHeap
+		byKey := make(map[keyT]entry, n)
+		for _, e := range ents {
+			byKey[e.item.key] = e
+		}
+		for _, e := range ents[:half] {
+			heap.Push(&h, e.item)
+		}
+		for i, e := range pushes {
+			heap.Push(&h, e.item)
+			popped[i] = byKey[heap.Pop(&h).(itemT).key]
+		}
+	}
+
+	var h itemHeap
+	fillHeap := func(ents []entry) {
+		h = make(itemHeap, 0, n)
+		for _, e := range ents {
+			h = append(h, e.item)
+		}
+		heap.Init(&h)
+	}
+	check := func(im impl, action string, ok bool, want entry) {
+		if !ok {
+			panic(fmt.Sprintf("%v: %v: want %q", im.name(), action, want.bkey))
+		}
+	}
+
+	for _, action := range []string{"min", "max", "popmin", "popmax", "push-pop"} {
+		b := newBlock(n)
+		for _, im := range impls {
+			var peek, pop func(want entry) bool
+			switch action {
+			case "min", "popmin", "push-pop":
+				if p, ok := im.(minPopper); ok {
+					peek, pop = p.peekMin, p.popMin
+				}
+			case "max", "popmax":
+				if p, ok := im.(maxPopper); ok {
+					peek, pop = p.peekMax, p.popMax
+				}
+			}
+			if peek == nil {
+				continue
+			}
+			switch action {
+			case "min":
+				b.add(im.name(), action, func() { fill(im, ents) }, func(i, _ int) {
+					check(im, action, peek(sorted[0]), sorted[0])
+				})
+			case "max":
+				b.add(im.name(), action, func() { fill(im, ents) }, func(i, _ int) {
+					check(im, action, peek(sorted[n-1]), sorted[n-1])
+				})
+			case "popmin":
+				b.addCount(im.name(), action, writeOps(im, n, true), func() { fill(im, ents) }, func(i, _ int) {
+					check(im, action, pop(sorted[i]), sorted[i])
+				})
+			case "popmax":
+				b.addCount(im.name(), action, writeOps(im, n, true), func() { fill(im, ents) }, func(i, _ int) {
+					check(im, action, pop(sorted[n-1-i]), sorted[n-1-i])
+				})
+			case "push-pop":
+				b.addCount(im.name(), action, writeOps(im, len(pushes), true), func() { fill(im, ents[:half]) }, func(i, _ int) {
+					im.set(pushes[i])
+					check(im, action, pop(popped[i]), popped[i])
+				})
+			}
+		}
+		switch action {
+		case "min":
+			b.add("container/heap", action, func() { fillHeap(ents) }, func(i, _ int) {
+				if h[0].key != sorted[0].item.key {
+					panic(action)
+				}
+			})
+		case "popmin":
+			b.add("container/heap", action, func() { fillHeap(ents) }, func(i, _ int) {
+				if heap.Pop(&h).(itemT).key != sorted[i].item.key {
+					panic(action)
+				}
+			})
+		case "push-pop":
+			b.addCount("container/heap", action, len(pushes), func() { fillHeap(ents[:half]) }, func(i, _ int) {
+				heap.Push(&h, pushes[i].item)
+				if heap.Pop(&h).(itemT).key != popped[i].item.key {
+					panic(action)
+				}
+			})
+		}
+		b.exec()
+	}
+}
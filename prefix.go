@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// -prefixes lists the prefix lengths, in bytes of the key, for the
+// prefix scan block.
+var prefixLengths = "4,8,12"
+
+// benchPrefixScans times visiting every key that starts with a given
+// prefix. The prefixes are cut from random keys, so each one matches
+// at least one key, and each run checks that it saw exactly the keys
+// that match.
+//
+// The end of each prefix's range is worked out with the prefixes, so
+// contenders that take it (prefixRanger) do not pay for it in the op.
+//
+// ents must already be in random order; the prefixes are taken from it.
+func benchPrefixScans(impls []impl, ents []entry, plens []int) {
+	println()
+	println("** prefix scan **")
+	println("Test visiting every key that starts with a prefix of a random key.")
+	for _, im := range impls {
+		if _, ok := im.(prefixScanner); ok {
+			fill(im, ents)
+		}
+	}
+
+	keys := make([]string, len(ents))
+	for i, e := range ents {
+		keys[i] = string(e.item.key)
+	}
+	sort.Strings(keys)
+
+	for _, plen := range plens {
+		prefixes := make([]entry, len(ents))
+		ends := make([]entry, len(ents))
+		want := make([]int, len(ents))
+		var total int
+		for i, e := range ents {
+			p := string(e.item.key)
+			if plen < len(p) {
+				p = p[:plen]
+			}
+			prefixes[i] = entry{item: itemT{key: keyT(p)}, bkey: []byte(p)}
+			if end := prefixEnd(prefixes[i].bkey); end != nil {
+				ends[i] = entry{item: itemT{key: keyT(end)}, bkey: end}
+			}
+			lo := sort.SearchStrings(keys, p)
+			hi := lo + sort.Search(len(keys)-lo, func(j int) bool {
+				return !strings.HasPrefix(keys[lo+j], p)
+			})
+			want[i] = hi - lo
+			total += want[i]
+		}
+		avg := max(1, total/len(ents))
+		count := rangeOps(len(ents), avg)
+
+		action := fmt.Sprintf("prefix-%d", plen)
+		fmt.Printf("prefix length %d: %d keys match on average\n", plen, avg)
+		b := newBlock(count)
+		for _, im := range impls {
+			ps, ok := im.(prefixScanner)
+			if !ok {
+				continue
+			}
+			n := count
+			if _, ok := im.(linearSeeker); ok && n > linearOps {
+				n = linearOps
+			}
+			scan := func(i int, fn func() bool) { ps.scanPrefix(prefixes[i], fn) }
+			if pr, ok := im.(prefixRanger); ok {
+				scan = func(i int, fn func() bool) { pr.scanPrefixRange(prefixes[i], ends[i], fn) }
+			}
+			b.addCount(im.name(), action, n, nil, func(i, _ int) {
+				var seen int
+				scan(i, func() bool {
+					seen++
+					return true
+				})
+				if seen != want[i] {
+					panic(fmt.Sprintf("%v: prefix %q: saw %d keys, want %d",
+						im.name(), prefixes[i].bkey, seen, want[i]))
+				}
+			})
+		}
+		b.exec()
+	}
+}
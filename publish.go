@@ -0,0 +1,187 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/tidwall/lotsa"
+)
+
+// -pubk lists how many writes happen between two published snapshots,
+// and -readers how many reader goroutines run beside the writer, for
+// the publish block. Every combination is run.
+var (
+	publishWrites = "100,10000"
+	readerCounts  = "1,4"
+)
+
+const (
+	// Every scanEvery-th read is an ascend of scanLength items
+	// instead of a get.
+	scanEvery  = 16
+	scanLength = 100
+)
+
+// benchPublish reproduces one writer publishing snapshots through an
+// atomic pointer to many readers. The writer updates every key in
+// random order and publishes a new snapshot every k writes; each read
+// loads the latest snapshot and does a get or, now and then, a short
+// scan on it.
+//
+// Contenders that do their own locking are also run live: the readers
+// share the writer's tree and no snapshots are taken, which is what
+// snapshots are meant to beat. Their twins without locking are run
+// live behind a sync.RWMutex (live-rw), to show what the built-in
+// locking buys over the obvious alternative.
+//
+// The write line's bytes/op is the heap retained per write once the
+// run is over, and the alloc/write line below it counts all bytes
+// allocated per write, garbage included. The read line is the total
+// over all readers for as long as the writer ran.
+//
+// ents must already be in random order.
+func benchPublish(impls []impl, ents []entry, writes, readers []int) {
+	println()
+	println("** publish **")
+	println("Test one writer publishing snapshots through an atomic pointer to concurrent readers.")
+	for _, k := range writes {
+		for _, r := range readers {
+			for _, im := range impls {
+				sn, ok := im.(snapshotter)
+				if !ok {
+					continue
+				}
+				fill(im, ents)
+				var cur atomic.Pointer[impl]
+				publish := func() {
+					snap := sn.snapshot()
+					cur.Store(&snap)
+				}
+				publish()
+				writeRead(im.name(), fmt.Sprintf("pub-%d-r%d", k, r), ents, r,
+					func(i int) {
+						im.set(ents[i])
+						if i%k == k-1 {
+							publish()
+						}
+					},
+					func() impl { return *cur.Load() })
+			}
+		}
+	}
+	for _, r := range readers {
+		for _, im := range impls {
+			lr, ok := im.(liveReader)
+			if !ok {
+				continue
+			}
+			action, live := fmt.Sprintf("live-r%d", r), im
+			if !lr.locked() {
+				a, ok := im.(ascender)
+				if !ok {
+					continue
+				}
+				action, live = fmt.Sprintf("live-rw-r%d", r), &rwLocked{impl: im, a: a}
+			}
+			fill(im, ents)
+			writeRead(im.name(), action, ents, r,
+				func(i int) { live.set(ents[i]) },
+				func() impl { return live })
+		}
+	}
+}
+
+// rwLocked puts a contender that does no locking of its own behind a
+// sync.RWMutex, for the live runs.
+type rwLocked struct {
+	impl
+	a  ascender
+	mu sync.RWMutex
+}
+
+func (l *rwLocked) set(e entry) {
+	l.mu.Lock()
+	l.impl.set(e)
+	l.mu.Unlock()
+}
+
+func (l *rwLocked) get(e entry) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.impl.get(e)
+}
+
+func (l *rwLocked) ascend(from entry, fn func() bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	l.a.ascend(from, fn)
+}
+
+// writeRead runs write for every index of ents on this goroutine while
+// readers goroutines read from whatever view returns, and prints the
+// write and read lines.
+func writeRead(label, action string, ents []entry, readers int, write func(i int), view func() impl) {
+	if traceMatch(label, action) {
+		stop := startTrace(label, action)
+		defer stop()
+	}
+	beforeTiming()
+	base := heapAlloc()
+	before := totalAlloc()
+
+	var done atomic.Bool
+	var reads atomic.Int64
+	var wg sync.WaitGroup
+	wg.Add(readers)
+	start := time.Now()
+	for r := 0; r < readers; r++ {
+		go func(r int) {
+			defer wg.Done()
+			var n int64
+			for j := r * len(ents) / readers; !done.Load(); j++ {
+				v := view()
+				e := ents[j%len(ents)]
+				if j%scanEvery == 0 {
+					if a, ok := v.(ascender); ok {
+						var seen int
+						a.ascend(e, func() bool {
+							seen++
+							return seen < scanLength
+						})
+						n++
+						continue
+					}
+				}
+				if !v.get(e) {
+					panic(fmt.Sprintf("%v: %q not found", label, e.item.key))
+				}
+				n++
+			}
+			reads.Add(n)
+		}(r)
+	}
+	for i := range ents {
+		write(i)
+	}
+	wdur := time.Since(start)
+	done.Store(true)
+	wg.Wait()
+	rdur := time.Since(start)
+
+	perWrite := float64(totalAlloc()-before) / float64(len(ents))
+	var grown uint64
+	if after := heapAlloc(); after > base {
+		grown = after - base
+	}
+	// view holds the latest snapshot, which counts as retained.
+	runtime.KeepAlive(view)
+	print_label(label, action+"-write")
+	lotsa.WriteOutput(os.Stdout, len(ents), 1, wdur, grown)
+	fmt.Printf("%-11s %-17s alloc/write %.1f bytes\n", "", "", perWrite)
+	print_label(label, action+"-read")
+	lotsa.WriteOutput(os.Stdout, int(reads.Load()), readers, rdur, 0)
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// -ranges lists the range lengths to scan: comma separated lengths,
+// "all" for a full scan, or "lo..hi" for the sweep lo, lo*10, ...,
+// up to hi.
+var rangeLengths = "1,100,10000,all"
+
+const allLength = -1
+
+func parseLengths(s string) ([]int, error) {
+	var lengths []int
+	for _, f := range strings.Split(s, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		if f == "all" {
+			lengths = append(lengths, allLength)
+			continue
+		}
+		if lo, hi, ok := strings.Cut(f, ".."); ok {
+			a, err1 := strconv.Atoi(lo)
+			b, err2 := strconv.Atoi(hi)
+			if err1 != nil || err2 != nil || a < 1 || b < a {
+				return nil, fmt.Errorf("bad range sweep %q", f)
+			}
+			for n := a; n <= b; n *= 10 {
+				lengths = append(lengths, n)
+			}
+			continue
+		}
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("bad range length %q", f)
+		}
+		lengths = append(lengths, n)
+	}
+	return lengths, nil
+}
+
+// parseCounts parses a comma separated list of positive numbers given
+// to the named flag.
+func parseCounts(name, s string) ([]int, error) {
+	var counts []int
+	for _, f := range strings.Split(s, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("bad %s value %q", name, f)
+		}
+		counts = append(counts, n)
+	}
+	return counts, nil
+}
+
+func lengthName(length int) string {
+	if length == allLength {
+		return "all"
+	}
+	return strconv.Itoa(length)
+}
+
+// rangeOps is how many ranges of the given length to time. It keeps
+// the number of items visited near 10 per key, like the pivot tests,
+// so long ranges do not take forever.
+func rangeOps(count, length int) int {
+	ops := count * 10 / length
+	if ops > count {
+		ops = count
+	}
+	if ops < 1 {
+		ops = 1
+	}
+	return ops
+}
+
+// linearOps caps the ops of contenders that seek in O(n).
+const linearOps = 100
+
+// benchRangeScans times scans of each length, ascending and
+// descending, starting at a random pivot. ns/op is per range, except
+// for "all", which scans everything once and reports per item, like
+// the scan block.
+//
+// ents must already be in random order; the pivots are taken from it.
+func benchRangeScans(impls []impl, ents []entry, lengths []int) {
+	println()
+	println("** range scan **")
+	println("Test scanning ranges of consecutive items starting at a random pivot.")
+	for _, im := range impls {
+		_, asc := im.(ascender)
+		_, desc := im.(descender)
+		if asc || desc {
+			fill(im, ents)
+		}
+	}
+	first, last := ents[0], ents[0]
+	for _, e := range ents {
+		if e.item.key < first.item.key {
+			first = e
+		}
+		if e.item.key > last.item.key {
+			last = e
+		}
+	}
+	for _, length := range lengths {
+		for _, dir := range []string{"asc", "desc"} {
+			action := "range-" + dir + "-" + lengthName(length)
+			count := len(ents)
+			if length != allLength {
+				count = rangeOps(len(ents), length)
+			}
+			b := newBlock(count)
+			for _, im := range impls {
+				scan := rangeScanner(im, dir)
+				if scan == nil {
+					continue
+				}
+				n := count
+				if _, ok := im.(linearSeeker); ok && length != allLength && n > linearOps {
+					n = linearOps
+				}
+				if length == allLength {
+					from := first
+					if dir == "desc" {
+						from = last
+					}
+					b.addCount(im.name(), action, n, nil, func(i, _ int) {
+						if i == 0 {
+							scan(from, func() bool { return true })
+						}
+					})
+					continue
+				}
+				b.addCount(im.name(), action, n, nil, func(i, _ int) {
+					var seen int
+					scan(ents[i], func() bool {
+						seen++
+						return seen < length
+					})
+				})
+			}
+			b.exec()
+		}
+	}
+}
+
+// rangeScanner returns im's scan in the direction dir, or nil.
+func rangeScanner(im impl, dir string) func(from entry, fn func() bool) {
+	switch dir {
+	case "asc":
+		if a, ok := im.(ascender); ok {
+			return a.ascend
+		}
+	case "desc":
+		if d, ok := im.(descender); ok {
+			return d.descend
+		}
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// pivotLength is how many keys a reverse block pivot run visits, like
+// the pivot blocks in main.
+const pivotLength = 10
+
+// benchReverse puts every ascending walk next to its descending twin,
+// for each contender that can go both ways:
+//
+//   - scan-*: every key, through the callback API.
+//   - iter-*: every key, through the library's iterator (First/Next
+//     and Last/Prev for tidwall, SeekToFirst/SeekToLast for badger).
+//   - pivot-*: pivotLength keys from a random pivot.
+//   - bounded-L-*: the L keys in [lo, hi) ascending, or in (lo, hi]
+//     descending. skipmap is left out: it has no bounded range, only
+//     Range from its smallest key, so either way would be a scan of
+//     everything below hi, which the bounded block already times as
+//     until-L.
+//
+// ns/op of the scans is per key. The block ends with how much slower
+// each descending walk is than the ascending one, per contender.
+//
+// ents must already be in random order.
+func benchReverse(impls []impl, ents []entry, lengths []int) {
+	println()
+	println("** reverse **")
+	println("Test each ascending walk next to the same walk descending.")
+	for _, im := range impls {
+		if _, ok := im.(descender); ok {
+			fill(im, ents)
+		}
+	}
+
+	n := len(ents)
+	sorted := append([]entry(nil), ents...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].item.key < sorted[j].item.key
+	})
+	first, last := sorted[0], sorted[n-1]
+	rank := make(map[keyT]int, n)
+	for i, e := range sorted {
+		rank[e.item.key] = i
+	}
+
+	type pair struct{ label, asc, desc string }
+	var pairs []pair
+	var blocks []*block
+
+	// A kind of walk is run count times; walk returns the op of its
+	// ascending or descending run, or nil if im cannot do it.
+	type kind struct {
+		name  string
+		count int
+		walk  func(im impl, desc bool) func(i, _ int)
+	}
+	kinds := []kind{
+		{"scan", n, func(im impl, desc bool) func(i, _ int) {
+			a, asc := im.(ascender)
+			d, dsc := im.(descender)
+			if !asc || !dsc {
+				return nil
+			}
+			return func(i, _ int) {
+				if i != 0 {
+					return
+				}
+				if desc {
+					d.descend(last, func() bool { return true })
+				} else {
+					a.ascend(first, func() bool { return true })
+				}
+			}
+		}},
+		{"iter", n, func(im impl, desc bool) func(i, _ int) {
+			it, ok := im.(iterScanner)
+			if !ok {
+				return nil
+			}
+			return func(i, _ int) {
+				if i == 0 {
+					it.iterScan(desc, func() bool { return true })
+				}
+			}
+		}},
+		{"pivot", n, func(im impl, desc bool) func(i, _ int) {
+			a, asc := im.(ascender)
+			d, dsc := im.(descender)
+			if !asc || !dsc {
+				return nil
+			}
+			return func(i, _ int) {
+				var seen int
+				visit := func() bool {
+					seen++
+					return seen < pivotLength
+				}
+				if desc {
+					d.descend(ents[i], visit)
+				} else {
+					a.ascend(ents[i], visit)
+				}
+			}
+		}},
+	}
+	for _, length := range lengths {
+		if length == allLength || length >= n {
+			continue
+		}
+		// As in benchBoundedRanges, lo is a random pivot moved back
+		// far enough from the end that hi is length keys later.
+		los := make([]entry, n)
+		his := make([]entry, n)
+		for i := range los {
+			r := min(rank[ents[i].item.key], n-1-length)
+			los[i], his[i] = sorted[r], sorted[r+length]
+		}
+		name := fmt.Sprintf("bounded-%d", length)
+		kinds = append(kinds, kind{name, rangeOps(n, length), func(im impl, desc bool) func(i, _ int) {
+			a, asc := im.(boundedRanger)
+			d, dsc := im.(boundedDescender)
+			if !asc || !dsc {
+				return nil
+			}
+			return func(i, _ int) {
+				var seen int
+				visit := func() bool {
+					seen++
+					return true
+				}
+				if desc {
+					d.descendRange(los[i], his[i], visit)
+				} else {
+					a.ascendRange(los[i], his[i], visit)
+				}
+				if seen != length {
+					panic(fmt.Sprintf("%v: saw %d keys, want %d", im.name(), seen, length))
+				}
+			}
+		}})
+	}
+
+	for _, k := range kinds {
+		b := newBlock(k.count)
+		for _, im := range impls {
+			asc, desc := k.walk(im, false), k.walk(im, true)
+			if asc == nil {
+				continue
+			}
+			p := pair{im.name(), k.name + "-asc", k.name + "-desc"}
+			count := k.count
+			if _, ok := im.(linearSeeker); ok && k.name != "scan" && count > linearOps {
+				count = linearOps
+			}
+			b.addCount(p.label, p.asc, count, nil, asc)
+			b.addCount(p.label, p.desc, count, nil, desc)
+			pairs = append(pairs, p)
+			blocks = append(blocks, b)
+		}
+		b.exec()
+	}
+
+	println()
+	println("** reverse asymmetry **")
+	println("How long each descending walk takes compared with the ascending one.")
+	for i, p := range pairs {
+		blocks[i].printRatio(runKey{p.label, p.desc}, runKey{p.label, p.asc}, p.asc)
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"unsafe"
+)
+
+// -snapk lists how many writes happen between two snapshots, and
+// -snapdepth how many of the latest snapshots are kept alive, for the
+// snapshot write runs. Every combination is run.
+var (
+	snapWrites = "10,1000"
+	snapDepths = "1,16"
+)
+
+// benchSnapshots measures copy-on-write snapshots (tidwall's Copy and
+// google's Clone):
+//
+//   - copy-lazy-N: the cost of taking a snapshot of a tree holding N
+//     items. Both copies are lazy, so this is O(1) at every N.
+//   - copy-write-N: a snapshot and then one set to it, which makes
+//     copy-on-write copy the path down to the key. The first-write
+//     line below it is the difference, what the copy really costs.
+//   - snap-k-d: updating every key in random order, taking a snapshot
+//     every k writes and keeping the last d of them alive, like MVCC
+//     readers would. The bytes/op lotsa reports is the memory retained
+//     per write, and the alloc/write line below it is the write
+//     amplification: all bytes allocated per write, garbage included.
+//   - shared after m mutations: how much of a snapshot is still shared
+//     with the tree it was taken from, after m writes to the tree.
+//
+// ents must already be in random order.
+func benchSnapshots(impls []impl, ents []entry, writes, depths []int) {
+	var snaps []impl
+	for _, im := range impls {
+		if _, ok := im.(snapshotter); ok {
+			snaps = append(snaps, im)
+		}
+	}
+
+	println()
+	println("** snapshot copy **")
+	println("Test the cost of taking a snapshot of a tree of each size.")
+	var sizes []int
+	for size := 1000; size < len(ents); size *= 10 {
+		sizes = append(sizes, size)
+	}
+	sizes = append(sizes, len(ents))
+	for _, size := range sizes {
+		lazy := fmt.Sprintf("copy-lazy-%d", size)
+		write := fmt.Sprintf("copy-write-%d", size)
+		b := newBlock(len(ents))
+		for _, im := range snaps {
+			sn := im.(snapshotter)
+			setup := func() { fill(im, ents[:size]) }
+			b.add(im.name(), lazy, setup, func(i, _ int) {
+				sn.snapshot()
+			})
+			b.add(im.name(), write, setup, func(i, _ int) {
+				sn.snapshot().set(ents[i%size])
+			})
+		}
+		b.exec()
+		for _, im := range snaps {
+			rl, ok1 := b.result(im.name(), lazy)
+			rw, ok2 := b.result(im.name(), write)
+			if !ok1 || !ok2 {
+				continue
+			}
+			print_label(im.name(), fmt.Sprintf("first-write-%d", size))
+			fmt.Printf("%.0f ns/op after the copy\n", max(0, rw.nsop()-rl.nsop()))
+		}
+	}
+
+	println()
+	println("** snapshot writes **")
+	println("Test updating every key, taking a snapshot every k writes and keeping the last d.")
+	itemSize := int(unsafe.Sizeof(itemT{}))
+	for _, k := range writes {
+		for _, depth := range depths {
+			action := fmt.Sprintf("snap-%d-%d", k, depth)
+			for _, im := range snaps {
+				sn := im.(snapshotter)
+				fill(im, ents)
+				ring := make([]impl, depth)
+				before := totalAlloc()
+				benchOps(im.name(), action, len(ents), 1, func(i, _ int) {
+					im.set(ents[i])
+					if i%k == k-1 {
+						ring[i/k%depth] = sn.snapshot()
+					}
+				})
+				perWrite := float64(totalAlloc()-before) / float64(len(ents))
+				fmt.Printf("%-11s %-17s alloc/write %.1f bytes, %.1fx the %d byte item\n",
+					"", "", perWrite, perWrite/float64(itemSize), itemSize)
+			}
+		}
+	}
+
+	println()
+	println("** snapshot sharing **")
+	println("Test how much a snapshot still shares with its tree after m writes to the tree.")
+	for _, im := range snaps {
+		sn := im.(snapshotter)
+		full := fillSize(im, ents)
+		print_label(im.name(), "full tree")
+		fmt.Printf("%s\n", memString(full))
+		for m := 1; ; m *= 100 {
+			if m > len(ents) {
+				m = len(ents)
+			}
+			fill(im, ents)
+			snap := sn.snapshot()
+			before := heapAlloc()
+			for i := 0; i < m; i++ {
+				im.set(ents[i])
+			}
+			var dup uint64
+			if after := heapAlloc(); after > before {
+				dup = after - before
+			}
+			shared := max(0, 100*(1-float64(dup)/float64(full)))
+			print_label(im.name(), fmt.Sprintf("shared-%d", m))
+			fmt.Printf("%s duplicated, %.1f%% of the tree still shared\n",
+				memString(dup), shared)
+			runtime.KeepAlive(snap)
+			if m == len(ents) {
+				break
+			}
+		}
+	}
+}
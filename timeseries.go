@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// recentWindow is how many of the newest keys a recent-window read
+// visits.
+const recentWindow = 100
+
+// benchTimeSeries times keys that only ever grow, like timestamps:
+//
+//   - append: insert N increasing keys into an empty structure, with
+//     the plain insert and, for tidwall, SetHint and Load.
+//   - trim: hold N keys, append a new one and delete the oldest.
+//   - recent: visit the newest recentWindow keys.
+//
+// Each is run with the timestamps in two encodings: dec, 16 digit
+// decimal strings like the keys of -keys num, and be,
+// 8 byte big-endian integers. badger/skiplist cannot take 8 byte keys
+// and runs dec only.
+func benchTimeSeries(impls []impl, n int) {
+	println()
+	println("** time series **")
+	println("Test appending increasing timestamps, trimming the oldest and reading the newest.")
+
+	// 2n timestamps, n to start with and n to append while trimming.
+	stamps := make([]int64, 2*n)
+	ts := int64(1_700_000_000_000_000)
+	for i := range stamps {
+		ts += 1 + rng.Int63n(1000)
+		stamps[i] = ts
+	}
+
+	for _, enc := range []string{"dec", "be"} {
+		pool := make([]entry, len(stamps))
+		for i, ts := range stamps {
+			switch enc {
+			case "dec":
+				item := int64ToItemT(ts)
+				pool[i] = entry{item: item, bkey: []byte(item.key)}
+			case "be":
+				bkey := binary.BigEndian.AppendUint64(nil, uint64(ts))
+				pool[i] = entry{item: itemT{key: keyT(bkey), val: valT(ts)}, bkey: bkey}
+			}
+		}
+		var ims []impl
+		for _, im := range impls {
+			if fits(im, pool) {
+				ims = append(ims, im)
+			}
+		}
+		head, tail := pool[:n], pool[n:]
+
+		b := newBlock(n)
+		for _, im := range ims {
+			b.add(im.name(), "append-"+enc, im.reset, func(i, _ int) {
+				im.set(head[i])
+			})
+			if h, ok := im.(hintSetter); ok {
+				b.add(im.name(), "append-hint-"+enc, im.reset, func(i, _ int) {
+					h.setHint(head[i])
+				})
+			}
+			if l, ok := im.(loader); ok {
+				b.add(im.name(), "append-load-"+enc, im.reset, func(i, _ int) {
+					l.load(head[i])
+				})
+			}
+		}
+		b.exec()
+
+		b = newBlock(n)
+		for _, im := range ims {
+			d, ok := im.(deleter)
+			if !ok {
+				continue
+			}
+			reserveArena(im, len(pool), n)
+			b.addCount(im.name(), "trim-"+enc, writeOps(im, n, false), func() {
+				fill(im, head)
+			}, func(i, _ int) {
+				im.set(tail[i])
+				d.del(head[i])
+			})
+		}
+		b.exec()
+
+		window := min(recentWindow, n)
+		from := tail[n-window]
+		b = newBlock(n)
+		for _, im := range ims {
+			a, ok := im.(ascender)
+			if !ok {
+				continue
+			}
+			count := n
+			if _, ok := im.(linearSeeker); ok {
+				count = linearOps
+			}
+			b.addCount(im.name(), "recent-"+enc, count, func() {
+				fill(im, tail)
+			}, func(i, _ int) {
+				var seen int
+				a.ascend(from, func() bool {
+					seen++
+					return true
+				})
+				if seen != window {
+					panic(fmt.Sprintf("%v: recent window holds %d keys, want %d",
+						im.name(), seen, window))
+				}
+			})
+		}
+		b.exec()
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime/trace"
+	"strings"
+)
+
+// -trace selects runs to capture with runtime/trace. The glob is
+// matched (path.Match syntax) against the action, e.g. "set-rand",
+// and against "label:action", e.g. "tidwall(G):get-*". Note that
+// a '*' does not match the '/' in labels like "badger/skiplist".
+var traceGlob string
+
+// -tracedir is where the trace files go, one per traced run.
+var traceDir = "."
+
+func checkTraceGlob() error {
+	if traceGlob == "" {
+		return nil
+	}
+	if _, err := path.Match(traceGlob, ""); err != nil {
+		return fmt.Errorf("bad -trace glob %q: %w", traceGlob, err)
+	}
+	return nil
+}
+
+func traceMatch(label, action string) bool {
+	if traceGlob == "" {
+		return false
+	}
+	for _, name := range []string{action, label + ":" + action} {
+		if ok, _ := path.Match(traceGlob, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// traceFileName turns "tidwall(G)", "set-rand" into
+// "trace.tidwall_G_.set-rand.out".
+func traceFileName(label, action string) string {
+	clean := func(s string) string {
+		return strings.Map(func(r rune) rune {
+			switch {
+			case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z',
+				r >= '0' && r <= '9', r == '-', r == '.':
+				return r
+			}
+			return '_'
+		}, s)
+	}
+	return "trace." + clean(label) + "." + clean(action) + ".out"
+}
+
+// startTrace starts runtime/trace into a fresh file for the run,
+// and returns the func that stops it. View the result with
+//
+//	go tool trace trace.tidwall_G_.set-rand.out
+//
+// The run shows up as a user task named after the label, with one
+// region per goroutine named after the action.
+func startTrace(label, action string) (stop func()) {
+	fn := filepath.Join(traceDir, traceFileName(label, action))
+	f, err := os.Create(fn)
+	if err != nil {
+		panic(err)
+	}
+	if err := trace.Start(f); err != nil {
+		panic(err)
+	}
+	return func() {
+		trace.Stop()
+		if err := f.Close(); err != nil {
+			panic(err)
+		}
+		fmt.Printf("(trace written to %v)\n", fn)
+	}
+}
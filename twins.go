@@ -0,0 +1,81 @@
+package main
+
+import "fmt"
+
+// Twins are two contenders that differ in one respect, such as a
+// key-only tree and the key/value tree of the same library. A pair is
+// named [subject, base]: the blocks that run twins report the
+// subject's time as a multiple of the base's.
+
+// twinsOf looks the named pairs up in impls and returns those with
+// both halves there.
+func twinsOf(impls []impl, names [][2]string) [][2]impl {
+	byName := make(map[string]impl, len(impls))
+	for _, im := range impls {
+		byName[im.name()] = im
+	}
+	var twins [][2]impl
+	for _, t := range names {
+		a, ok1 := byName[t[0]]
+		b, ok2 := byName[t[1]]
+		if ok1 && ok2 {
+			twins = append(twins, [2]impl{a, b})
+		}
+	}
+	return twins
+}
+
+// benchTwins runs each action on both halves of every pair, one block
+// per action, and returns the blocks by action:
+//
+//   - set-rand: set every key into an empty structure.
+//   - get-rand: get every key from a full one.
+//   - scan: ascend over every key, timed as one op.
+//
+// ents must already be in random order.
+func benchTwins(twins [][2]impl, ents []entry, actions []string) map[string]*block {
+	blocks := make(map[string]*block, len(actions))
+	for _, action := range actions {
+		count := len(ents)
+		if action == "scan" {
+			count = 1
+		}
+		b := newBlock(count)
+		for _, t := range twins {
+			for _, im := range t {
+				switch action {
+				case "set-rand":
+					b.add(im.name(), action, im.reset, func(i, _ int) {
+						im.set(ents[i])
+					})
+				case "get-rand":
+					b.add(im.name(), action, func() { fill(im, ents) }, func(i, _ int) {
+						if !im.get(ents[i]) {
+							panic(fmt.Sprintf("%v: %q not found", im.name(), ents[i].item.key))
+						}
+					})
+				case "scan":
+					a, ok := im.(ascender)
+					if !ok {
+						continue
+					}
+					b.add(im.name(), action, func() { fill(im, ents) }, func(i, _ int) {
+						a.ascend(entry{}, func() bool { return true })
+					})
+				}
+			}
+		}
+		b.exec()
+		blocks[action] = b
+	}
+	return blocks
+}
+
+// printTwinRatios prints how long the subject of t took for each
+// action, as a multiple of the base.
+func printTwinRatios(t [2]impl, blocks map[string]*block, actions []string) {
+	subject, base := t[0].name(), t[1].name()
+	for _, action := range actions {
+		blocks[action].printRatio(runKey{subject, action}, runKey{base, action}, base)
+	}
+}
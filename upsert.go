@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// benchUpsert overwrites the value of keys that are already there,
+// which takes the replace path of each library's insert (ReplaceOrInsert,
+// Set, Store, Put, Insert) and never splits a node:
+//
+//   - update-seq, update-rand: overwrite every key, in sorted and in
+//     random order.
+//   - rmw-rand: read-modify-write, a get and then a set of every key,
+//     in random order.
+//
+// After each run the structure must still hold exactly N keys.
+//
+// ents must already be in random order.
+func benchUpsert(impls []impl, ents []entry) {
+	println()
+	println("** upsert **")
+	println("Test overwriting the values of keys that are already there.")
+
+	updated := make([]entry, len(ents))
+	for i, e := range ents {
+		e.item.val++
+		updated[i] = e
+	}
+	sortedUpdated := append([]entry(nil), updated...)
+	sort.Slice(sortedUpdated, func(i, j int) bool {
+		return sortedUpdated[i].item.key < sortedUpdated[j].item.key
+	})
+
+	for _, action := range []string{"update-seq", "update-rand", "rmw-rand"} {
+		b := newBlock(len(ents))
+		for _, im := range impls {
+			setup := func() { fill(im, ents) }
+			switch action {
+			case "update-seq":
+				b.add(im.name(), action, setup, func(i, _ int) {
+					im.set(sortedUpdated[i])
+				})
+			case "update-rand":
+				b.add(im.name(), action, setup, func(i, _ int) {
+					im.set(updated[i])
+				})
+			case "rmw-rand":
+				b.add(im.name(), action, setup, func(i, _ int) {
+					if !im.get(ents[i]) {
+						panic(fmt.Sprintf("%v: %q not found", im.name(), ents[i].bkey))
+					}
+					im.set(updated[i])
+				})
+			}
+		}
+		b.exec()
+		for _, im := range impls {
+			if n := im.len(); n != len(ents) {
+				panic(fmt.Sprintf("%v: %v: %d keys after, want %d", im.name(), action, n, len(ents)))
+			}
+		}
+	}
+}